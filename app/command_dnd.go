@@ -0,0 +1,54 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mattermost/mattermost-server/model"
+	goi18n "github.com/nicksnyder/go-i18n/i18n"
+)
+
+type DndProvider struct {
+}
+
+const (
+	CMD_DND = "dnd"
+)
+
+func init() {
+	RegisterCommandProvider(&DndProvider{})
+}
+
+func (me *DndProvider) GetTrigger() string {
+	return CMD_DND
+}
+
+func (me *DndProvider) GetCommand(a *App, T goi18n.TranslateFunc) *model.Command {
+	return &model.Command{
+		Trigger:          CMD_DND,
+		AutoComplete:     true,
+		AutoCompleteDesc: T("api.command_dnd.desc"),
+		AutoCompleteHint: T("api.command_dnd.hint"),
+		DisplayName:      T("api.command_dnd.name"),
+	}
+}
+
+func (me *DndProvider) DoCommand(a *App, args *model.CommandArgs, message string) *model.CommandResponse {
+	var duration time.Duration
+	if message = strings.TrimSpace(message); message != "" {
+		parsed, parseErr := time.ParseDuration(message)
+		if parseErr != nil || parsed <= 0 {
+			return &model.CommandResponse{Text: args.T("api.command_dnd.invalid_duration.app_error", map[string]interface{}{"Duration": message}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+		}
+		duration = parsed
+	}
+
+	if err := a.SetStatusFromCommand(args.Session.UserId, model.STATUS_DND, duration); err != nil {
+		return &model.CommandResponse{Text: args.T("api.command_dnd.fail.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	return &model.CommandResponse{Text: args.T("api.command_dnd.success"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestDndProvider(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	DndP := DndProvider{}
+	args := &model.CommandArgs{
+		T:         func(s string, args ...interface{}) string { return s },
+		ChannelId: th.BasicChannel.Id,
+		TeamId:    th.BasicTeam.Id,
+		Session:   model.Session{UserId: th.BasicUser.Id},
+	}
+
+	tests := []struct {
+		desc     string
+		expected string
+		msg      string
+	}{
+		{
+			desc:     "Set do not disturb with no duration",
+			expected: "api.command_dnd.success",
+			msg:      "",
+		},
+		{
+			desc:     "Set do not disturb for 30 minutes",
+			expected: "api.command_dnd.success",
+			msg:      "30m",
+		},
+		{
+			desc:     "Invalid duration is rejected",
+			expected: "api.command_dnd.invalid_duration.app_error",
+			msg:      "not-a-duration",
+		},
+		{
+			desc:     "Zero duration is rejected",
+			expected: "api.command_dnd.invalid_duration.app_error",
+			msg:      "0m",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			actual := DndP.DoCommand(th.App, args, test.msg).Text
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+
+	status, err := th.App.GetStatus(th.BasicUser.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, model.STATUS_DND, status.Status)
+}
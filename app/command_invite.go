@@ -16,6 +16,11 @@ type InviteProvider struct {
 
 const (
 	CMD_INVITE = "invite"
+
+	// MAX_USERS_PER_INVITE_COMMAND caps the number of @-mentions a single
+	// /invite can carry so the command can't be abused to spam-add a team's
+	// whole membership into a channel in one shot.
+	MAX_USERS_PER_INVITE_COMMAND = 20
 )
 
 func init() {
@@ -36,6 +41,42 @@ func (me *InviteProvider) GetCommand(a *App, T goi18n.TranslateFunc) *model.Comm
 	}
 }
 
+// splitUsernamesAndRest pulls the leading run of "@username" tokens off of
+// message, stopping at the first token that isn't an @-mention (the rest of
+// the message, if any, is the optional channel spec). Usernames are
+// de-duplicated while preserving the order they were first seen in, and the
+// list is capped at MAX_USERS_PER_INVITE_COMMAND entries.
+func splitUsernamesAndRest(message string) ([]string, string) {
+	tokens := strings.Fields(message)
+
+	seen := make(map[string]bool)
+	usernames := []string{}
+	rest := []string{}
+
+	i := 0
+	for ; i < len(tokens); i++ {
+		if !strings.HasPrefix(tokens[i], "@") {
+			break
+		}
+
+		username := strings.TrimPrefix(tokens[i], "@")
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+
+		usernames = append(usernames, username)
+		if len(usernames) >= MAX_USERS_PER_INVITE_COMMAND {
+			i++
+			break
+		}
+	}
+
+	rest = tokens[i:]
+
+	return usernames, strings.Join(rest, " ")
+}
+
 func (me *InviteProvider) DoCommand(a *App, args *model.CommandArgs, message string) *model.CommandResponse {
 	if message == "" {
 		return &model.CommandResponse{Text: args.T("api.command_invite.missing_message.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
@@ -43,23 +84,21 @@ func (me *InviteProvider) DoCommand(a *App, args *model.CommandArgs, message str
 
 	l4g.Debug(message)
 
-	splitMessage := strings.SplitN(message, " ", 2)
-	targetUsername := splitMessage[0]
-	targetUsername = strings.TrimPrefix(targetUsername, "@")
-
-	var userProfile *model.User
-	if result := <-a.Srv.Store.User().GetByUsername(targetUsername); result.Err != nil {
-		l4g.Error(result.Err.Error())
-		return &model.CommandResponse{Text: args.T("api.command_invite.missing_user.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
-	} else {
-		userProfile = result.Data.(*model.User)
+	usernames, rest := splitUsernamesAndRest(message)
+	if len(usernames) == 0 {
+		// Backwards compatible with the single-user, no-@-prefix form.
+		splitMessage := strings.SplitN(message, " ", 2)
+		usernames = []string{strings.TrimPrefix(splitMessage[0], "@")}
+		if len(splitMessage) > 1 {
+			rest = splitMessage[1]
+		}
 	}
 
 	var channelToJoin *model.Channel
 	var err *model.AppError
-	// User set a channel to add the invited user
-	if len(splitMessage) > 1 && splitMessage[1] != "" {
-		targetChannelName := strings.TrimPrefix(strings.TrimSpace(splitMessage[1]), "~")
+	// User set a channel to add the invited user(s) to
+	if rest != "" {
+		targetChannelName := strings.TrimPrefix(strings.TrimSpace(rest), "~")
 
 		if channelToJoin, err = a.GetChannelByName(targetChannelName, args.TeamId); err != nil {
 			return &model.CommandResponse{Text: args.T("api.command_invite.channel.error", map[string]interface{}{"Channel": targetChannelName}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
@@ -76,27 +115,138 @@ func (me *InviteProvider) DoCommand(a *App, args *model.CommandArgs, message str
 		return &model.CommandResponse{Text: args.T("api.command_invite.directchannel.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
 	}
 
-	// Check if user is already in the channel
-	_, err = a.GetChannelMember(channelToJoin.Id, userProfile.Id)
-	if err == nil {
-		return &model.CommandResponse{Text: args.T("api.command_invite.user_already_in_channel.app_error", map[string]interface{}{"User": userProfile.Username}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	// Permission to manage the channel's membership is computed once, up
+	// front, rather than re-derived for every invited user.
+	if requiredPermission := channelMembershipPermission(channelToJoin); !a.SessionHasPermissionToChannel(args.Session, channelToJoin.Id, requiredPermission) {
+		return &model.CommandResponse{Text: args.T("api.command_invite.permission.app_error", map[string]interface{}{"User": usernames[0], "Channel": channelToJoin.Name}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	// Single user, with or without an explicit channel: keep the original
+	// terse behavior instead of the multi-user summary.
+	if len(usernames) == 1 {
+		return me.addUser(a, args, usernames[0], channelToJoin)
+	}
+
+	added := []string{}
+	alreadyInChannel := []string{}
+	notOnTeam := []string{}
+	unknownUser := []string{}
+	failed := []string{}
+
+	for _, username := range usernames {
+		outcome := me.inviteUserToChannel(a, args, username, channelToJoin)
+		switch outcome.result {
+		case inviteResultAdded:
+			added = append(added, outcome.username)
+		case inviteResultAlreadyInChannel:
+			alreadyInChannel = append(alreadyInChannel, outcome.username)
+		case inviteResultNotOnTeam:
+			notOnTeam = append(notOnTeam, outcome.username)
+		case inviteResultUnknownUser:
+			unknownUser = append(unknownUser, username)
+		case inviteResultFailed:
+			failed = append(failed, outcome.username)
+		}
+	}
+
+	return &model.CommandResponse{
+		Text:         args.T("api.command_invite.summary", summaryProps(added, alreadyInChannel, notOnTeam, unknownUser, failed, channelToJoin)),
+		ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL,
+	}
+}
+
+// channelMembershipPermission returns the permission a caller needs in order
+// to manage membership of channel, based on its type.
+func channelMembershipPermission(channel *model.Channel) string {
+	if channel.Type == model.CHANNEL_PRIVATE {
+		return model.PERMISSION_MANAGE_PRIVATE_CHANNEL_MEMBERS
 	}
+	return model.PERMISSION_MANAGE_PUBLIC_CHANNEL_MEMBERS
+}
+
+type inviteResult int
+
+const (
+	inviteResultAdded inviteResult = iota
+	inviteResultAlreadyInChannel
+	inviteResultNotOnTeam
+	inviteResultUnknownUser
+	inviteResultFailed
+)
+
+type inviteOutcome struct {
+	username string
+	result   inviteResult
+	err      *model.AppError
+}
 
-	if channelToJoin.Type == model.CHANNEL_OPEN && !a.SessionHasPermissionToChannel(args.Session, channelToJoin.Id, model.PERMISSION_MANAGE_PUBLIC_CHANNEL_MEMBERS) {
-		return &model.CommandResponse{Text: args.T("api.command_invite.permission.app_error", map[string]interface{}{"User": userProfile.Username, "Channel": channelToJoin.Name}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+// inviteUserToChannel resolves username and, if necessary, adds them to the
+// team that owns channelToJoin before adding them to the channel itself. A
+// user who isn't yet a member of that team is only auto-added if the caller
+// holds PERMISSION_ADD_USER_TO_TEAM; otherwise the outcome is
+// inviteResultNotOnTeam so the caller gets a precise reason instead of a
+// generic failure.
+func (me *InviteProvider) inviteUserToChannel(a *App, args *model.CommandArgs, username string, channelToJoin *model.Channel) inviteOutcome {
+	result := <-a.Srv.Store.User().GetByUsername(username)
+	if result.Err != nil {
+		return inviteOutcome{username: username, result: inviteResultUnknownUser}
 	}
+	userProfile := result.Data.(*model.User)
 
-	if channelToJoin.Type == model.CHANNEL_PRIVATE && !a.SessionHasPermissionToChannel(args.Session, channelToJoin.Id, model.PERMISSION_MANAGE_PRIVATE_CHANNEL_MEMBERS) {
-		return &model.CommandResponse{Text: args.T("api.command_invite.permission.app_error", map[string]interface{}{"User": userProfile.Username, "Channel": channelToJoin.Name}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	if _, err := a.GetChannelMember(channelToJoin.Id, userProfile.Id); err == nil {
+		return inviteOutcome{username: userProfile.Username, result: inviteResultAlreadyInChannel}
+	}
+
+	if _, err := a.GetTeamMember(channelToJoin.TeamId, userProfile.Id); err != nil {
+		if !a.SessionHasPermissionToTeam(args.Session, channelToJoin.TeamId, model.PERMISSION_ADD_USER_TO_TEAM) {
+			return inviteOutcome{username: userProfile.Username, result: inviteResultNotOnTeam}
+		}
+
+		if _, err := a.AddTeamMember(channelToJoin.TeamId, userProfile.Id); err != nil {
+			l4g.Error(err.Error())
+			return inviteOutcome{username: userProfile.Username, result: inviteResultFailed, err: err}
+		}
 	}
 
 	if _, err := a.AddChannelMember(userProfile.Id, channelToJoin, args.Session.UserId, ""); err != nil {
+		l4g.Error(err.Error())
+		return inviteOutcome{username: userProfile.Username, result: inviteResultFailed, err: err}
+	}
+
+	return inviteOutcome{username: userProfile.Username, result: inviteResultAdded}
+}
+
+func (me *InviteProvider) addUser(a *App, args *model.CommandArgs, username string, channelToJoin *model.Channel) *model.CommandResponse {
+	outcome := me.inviteUserToChannel(a, args, username, channelToJoin)
+
+	switch outcome.result {
+	case inviteResultUnknownUser:
+		return &model.CommandResponse{Text: args.T("api.command_invite.missing_user.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	case inviteResultAlreadyInChannel:
+		return &model.CommandResponse{Text: args.T("api.command_invite.user_already_in_channel.app_error", map[string]interface{}{"User": outcome.username}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	case inviteResultNotOnTeam:
+		return &model.CommandResponse{Text: args.T("api.command_invite.user_not_on_team.app_error", map[string]interface{}{"User": outcome.username}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	case inviteResultFailed:
 		return &model.CommandResponse{Text: args.T("api.command_invite.fail.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
 	}
 
 	if args.ChannelId != channelToJoin.Id {
-		return &model.CommandResponse{Text: args.T("api.command_invite.success", map[string]interface{}{"User": userProfile.Username, "Channel": channelToJoin.Name}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+		return &model.CommandResponse{Text: args.T("api.command_invite.success", map[string]interface{}{"User": outcome.username, "Channel": channelToJoin.Name}), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
 	}
 
 	return &model.CommandResponse{}
 }
+
+// summaryProps builds the translation args for the multi-user /invite
+// summary message, rendering each non-empty outcome bucket as a
+// comma-separated list of usernames.
+func summaryProps(added, alreadyInChannel, notOnTeam, unknownUser, failed []string, channel *model.Channel) map[string]interface{} {
+	return map[string]interface{}{
+		"Channel":          channel.Name,
+		"Added":            strings.Join(added, ", "),
+		"AlreadyInChannel": strings.Join(alreadyInChannel, ", "),
+		"NotOnTeam":        strings.Join(notOnTeam, ", "),
+		"UnknownUser":      strings.Join(unknownUser, ", "),
+		"Failed":           strings.Join(failed, ", "),
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2015-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"strings"
+
+	l4g "github.com/alecthomas/log4go"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/utils"
+	goi18n "github.com/nicksnyder/go-i18n/i18n"
+)
+
+type InvitePeopleProvider struct {
+}
+
+const (
+	CMD_INVITE_PEOPLE = "invite_people"
+)
+
+func init() {
+	RegisterCommandProvider(&InvitePeopleProvider{})
+}
+
+func (me *InvitePeopleProvider) GetTrigger() string {
+	return CMD_INVITE_PEOPLE
+}
+
+func (me *InvitePeopleProvider) GetCommand(a *App, T goi18n.TranslateFunc) *model.Command {
+	return &model.Command{
+		Trigger:          CMD_INVITE_PEOPLE,
+		AutoComplete:     true,
+		AutoCompleteDesc: T("api.command_invite_people.desc"),
+		AutoCompleteHint: T("api.command_invite_people.hint"),
+		DisplayName:      T("api.command_invite_people.name"),
+	}
+}
+
+func (me *InvitePeopleProvider) DoCommand(a *App, args *model.CommandArgs, message string) *model.CommandResponse {
+	if !a.Config().TeamSettings.EnableUserCreation {
+		return &model.CommandResponse{Text: args.T("api.command_invite_people.disabled.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	if !a.Config().EmailSettings.EnableEmailInvitations {
+		return &model.CommandResponse{Text: args.T("api.command_invite_people.email_invitations_disabled.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	if !a.SessionHasPermissionToTeam(args.Session, args.TeamId, model.PERMISSION_INVITE_USER) ||
+		!a.SessionHasPermissionToTeam(args.Session, args.TeamId, model.PERMISSION_ADD_USER_TO_TEAM) {
+		return &model.CommandResponse{Text: args.T("api.command_invite_people.permission.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return &model.CommandResponse{Text: args.T("api.command_invite_people.missing_message.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	emailList := strings.Fields(message)
+
+	invalidEmails := []string{}
+	validEmails := []string{}
+	for _, email := range emailList {
+		if !utils.IsValidEmail(email) {
+			invalidEmails = append(invalidEmails, email)
+			continue
+		}
+		validEmails = append(validEmails, email)
+	}
+
+	if len(invalidEmails) > 0 {
+		return &model.CommandResponse{
+			Text:         args.T("api.command_invite_people.invalid_email.app_error", map[string]interface{}{"Emails": strings.Join(invalidEmails, ", ")}),
+			ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL,
+		}
+	}
+
+	if err := a.InviteNewUsersToTeam(validEmails, args.TeamId, args.Session.UserId); err != nil {
+		l4g.Error(err.Error())
+		return &model.CommandResponse{Text: args.T("api.command_invite_people.fail.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	return &model.CommandResponse{
+		Text:         args.T("api.command_invite_people.sent", map[string]interface{}{"Emails": strings.Join(validEmails, ", ")}),
+		ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL,
+	}
+}
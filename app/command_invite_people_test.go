@@ -0,0 +1,87 @@
+// Copyright (c) 2015-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestInvitePeopleProvider(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	InvitePeopleP := InvitePeopleProvider{}
+	args := &model.CommandArgs{
+		T:         func(s string, args ...interface{}) string { return s },
+		ChannelId: th.BasicChannel.Id,
+		TeamId:    th.BasicTeam.Id,
+		Session: model.Session{
+			UserId:      th.BasicUser.Id,
+			TeamMembers: []*model.TeamMember{{TeamId: th.BasicTeam.Id, Roles: model.TEAM_USER_ROLE_ID}},
+		},
+	}
+
+	tests := []struct {
+		desc     string
+		expected string
+		msg      string
+		setup    func()
+		teardown func()
+	}{
+		{
+			desc:     "Missing emails in the command",
+			expected: "api.command_invite_people.missing_message.app_error",
+			msg:      "",
+		},
+		{
+			desc:     "Email invitations disabled",
+			expected: "api.command_invite_people.email_invitations_disabled.app_error",
+			msg:      "valid@example.com",
+			setup: func() {
+				th.App.UpdateConfig(func(cfg *model.Config) { *cfg.EmailSettings.EnableEmailInvitations = false })
+			},
+			teardown: func() {
+				th.App.UpdateConfig(func(cfg *model.Config) { *cfg.EmailSettings.EnableEmailInvitations = true })
+			},
+		},
+		{
+			desc:     "Caller lacks permission to invite",
+			expected: "api.command_invite_people.permission.app_error",
+			msg:      "valid@example.com",
+			setup: func() {
+				args.Session.TeamMembers = []*model.TeamMember{{TeamId: th.BasicTeam.Id, Roles: ""}}
+			},
+			teardown: func() {
+				args.Session.TeamMembers = []*model.TeamMember{{TeamId: th.BasicTeam.Id, Roles: model.TEAM_USER_ROLE_ID}}
+			},
+		},
+		{
+			desc:     "Mix of valid and invalid addresses is rejected with the bad ones listed",
+			expected: "api.command_invite_people.invalid_email.app_error",
+			msg:      "valid@example.com not-an-email",
+		},
+		{
+			desc:     "Valid addresses are invited",
+			expected: "api.command_invite_people.sent",
+			msg:      "valid1@example.com valid2@example.com",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			if test.setup != nil {
+				test.setup()
+			}
+			if test.teardown != nil {
+				defer test.teardown()
+			}
+			actual := InvitePeopleP.DoCommand(th.App, args, test.msg).Text
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
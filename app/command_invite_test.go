@@ -36,6 +36,10 @@ func TestInviteProvider(t *testing.T) {
 	userAndDisplayChannel := "@" + th.BasicUser2.Username + " ~" + channel.DisplayName + " "
 	userAndPrivateChannel := "@" + th.BasicUser2.Username + " ~" + privateChannel.Name
 	userAndDMChannel := "@" + basicUser3.Username + " ~" + dmChannel.Name
+	basicUser5 := th.CreateUser()
+	th.LinkUserToTeam(basicUser5, th.BasicTeam)
+	mixedSuccessAndFailure := "@" + basicUser5.Username + " @" + basicUser4.Username
+	duplicateUsernames := "@" + basicUser3.Username + " @" + basicUser3.Username
 
 	tests := []struct {
 		desc     string
@@ -88,8 +92,8 @@ func TestInviteProvider(t *testing.T) {
 			msg:      "invalidUser321",
 		},
 		{
-			desc:     "try to add a user which is not part of the team",
-			expected: "api.command_invite.fail.app_error",
+			desc:     "try to add a user which is not part of the team and caller cannot add them",
+			expected: "api.command_invite.user_not_on_team.app_error",
 			msg:      basicUser4.Username,
 		},
 		{
@@ -97,6 +101,16 @@ func TestInviteProvider(t *testing.T) {
 			expected: "api.command_invite.directchannel.app_error",
 			msg:      userAndDMChannel,
 		},
+		{
+			desc:     "Bulk invite with a mix of a successful add and a user not on the team",
+			expected: "api.command_invite.summary",
+			msg:      mixedSuccessAndFailure,
+		},
+		{
+			desc:     "Duplicate usernames are de-duplicated to a single add",
+			expected: "",
+			msg:      duplicateUsernames,
+		},
 	}
 
 	for _, test := range tests {
@@ -105,4 +119,50 @@ func TestInviteProvider(t *testing.T) {
 			assert.Equal(t, test.expected, actual)
 		})
 	}
-}
\ No newline at end of file
+}
+
+// TestInviteProvider_CrossTeam locks down the auto-add-to-team behavior: a
+// caller with PERMISSION_ADD_USER_TO_TEAM can pull a user from another team
+// straight into the channel, while a caller without that permission is told
+// they can't rather than hitting the generic failure message.
+func TestInviteProvider_CrossTeam(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	InviteP := InviteProvider{}
+
+	otherTeamUser := th.CreateUser()
+	th.LinkUserToTeam(otherTeamUser, th.CreateTeam())
+
+	t.Run("role-restricted caller cannot pull in a user from another team", func(t *testing.T) {
+		args := &model.CommandArgs{
+			T:         func(s string, args ...interface{}) string { return s },
+			ChannelId: th.BasicChannel.Id,
+			TeamId:    th.BasicTeam.Id,
+			Session:   model.Session{UserId: th.BasicUser.Id, TeamMembers: []*model.TeamMember{{TeamId: th.BasicTeam.Id, Roles: model.TEAM_USER_ROLE_ID}}},
+		}
+
+		actual := InviteP.DoCommand(th.App, args, otherTeamUser.Username).Text
+		assert.Equal(t, "api.command_invite.user_not_on_team.app_error", actual)
+
+		if _, err := th.App.GetTeamMember(th.BasicTeam.Id, otherTeamUser.Id); err == nil {
+			t.Fatal("user should not have been added to the team")
+		}
+	})
+
+	t.Run("team admin can auto-add a user from another team", func(t *testing.T) {
+		args := &model.CommandArgs{
+			T:         func(s string, args ...interface{}) string { return s },
+			ChannelId: th.BasicChannel.Id,
+			TeamId:    th.BasicTeam.Id,
+			Session:   model.Session{UserId: th.BasicUser.Id, TeamMembers: []*model.TeamMember{{TeamId: th.BasicTeam.Id, Roles: model.TEAM_ADMIN_ROLE_ID}}},
+		}
+
+		actual := InviteP.DoCommand(th.App, args, otherTeamUser.Username).Text
+		assert.Equal(t, "", actual)
+
+		if _, err := th.App.GetTeamMember(th.BasicTeam.Id, otherTeamUser.Id); err != nil {
+			t.Fatal("user should have been added to the team")
+		}
+	})
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/mattermost-server/model"
+	goi18n "github.com/nicksnyder/go-i18n/i18n"
+)
+
+type OfflineProvider struct {
+}
+
+const (
+	CMD_OFFLINE = "offline"
+)
+
+func init() {
+	RegisterCommandProvider(&OfflineProvider{})
+}
+
+func (me *OfflineProvider) GetTrigger() string {
+	return CMD_OFFLINE
+}
+
+func (me *OfflineProvider) GetCommand(a *App, T goi18n.TranslateFunc) *model.Command {
+	return &model.Command{
+		Trigger:          CMD_OFFLINE,
+		AutoComplete:     true,
+		AutoCompleteDesc: T("api.command_offline.desc"),
+		DisplayName:      T("api.command_offline.name"),
+	}
+}
+
+func (me *OfflineProvider) DoCommand(a *App, args *model.CommandArgs, message string) *model.CommandResponse {
+	if err := a.SetStatusFromCommand(args.Session.UserId, model.STATUS_OFFLINE, 0); err != nil {
+		return &model.CommandResponse{Text: args.T("api.command_offline.fail.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	return &model.CommandResponse{Text: args.T("api.command_offline.success"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+}
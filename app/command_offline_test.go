@@ -0,0 +1,32 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestOfflineProvider(t *testing.T) {
+	th := Setup().InitBasic()
+	defer th.TearDown()
+
+	OfflineP := OfflineProvider{}
+	args := &model.CommandArgs{
+		T:         func(s string, args ...interface{}) string { return s },
+		ChannelId: th.BasicChannel.Id,
+		TeamId:    th.BasicTeam.Id,
+		Session:   model.Session{UserId: th.BasicUser.Id},
+	}
+
+	actual := OfflineP.DoCommand(th.App, args, "").Text
+	assert.Equal(t, "api.command_offline.success", actual)
+
+	status, err := th.App.GetStatus(th.BasicUser.Id)
+	assert.Nil(t, err)
+	assert.Equal(t, model.STATUS_OFFLINE, status.Status)
+}
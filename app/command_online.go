@@ -0,0 +1,41 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"github.com/mattermost/mattermost-server/model"
+	goi18n "github.com/nicksnyder/go-i18n/i18n"
+)
+
+type OnlineProvider struct {
+}
+
+const (
+	CMD_ONLINE = "online"
+)
+
+func init() {
+	RegisterCommandProvider(&OnlineProvider{})
+}
+
+func (me *OnlineProvider) GetTrigger() string {
+	return CMD_ONLINE
+}
+
+func (me *OnlineProvider) GetCommand(a *App, T goi18n.TranslateFunc) *model.Command {
+	return &model.Command{
+		Trigger:          CMD_ONLINE,
+		AutoComplete:     true,
+		AutoCompleteDesc: T("api.command_online.desc"),
+		DisplayName:      T("api.command_online.name"),
+	}
+}
+
+func (me *OnlineProvider) DoCommand(a *App, args *model.CommandArgs, message string) *model.CommandResponse {
+	if err := a.SetStatusFromCommand(args.Session.UserId, model.STATUS_ONLINE, 0); err != nil {
+		return &model.CommandResponse{Text: args.T("api.command_online.fail.app_error"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+	}
+
+	return &model.CommandResponse{Text: args.T("api.command_online.success"), ResponseType: model.COMMAND_RESPONSE_TYPE_EPHEMERAL}
+}
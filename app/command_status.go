@@ -0,0 +1,93 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	"sync"
+	"time"
+
+	l4g "github.com/alecthomas/log4go"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+// dndExpiryCheckInterval is how often runDNDExpiryTicker polls the Status
+// table for elapsed timers.
+const dndExpiryCheckInterval = time.Minute
+
+// dndExpiryTickers holds one *sync.Once per *App so each App instance gets
+// its own expiry-clearing goroutine. A package-level sync.Once here would
+// only ever fire for the first App constructed in the process, leaving
+// every other App's /dnd timers never auto-clearing.
+var dndExpiryTickers sync.Map
+
+// SetStatusFromCommand updates userId's manual status and broadcasts the
+// change to their open sessions. When duration is non-zero, as with /dnd's
+// optional timer, the expiry is persisted on the Status row itself and
+// cleared by a periodic lookup rather than an in-process timer, so it
+// survives a restart or failover to another node.
+func (a *App) SetStatusFromCommand(userId, status string, duration time.Duration) *model.AppError {
+	var expiresAt int64
+	if duration > 0 {
+		expiresAt = model.GetMillis() + duration.Nanoseconds()/int64(time.Millisecond)
+	}
+
+	statusObj := &model.Status{
+		UserId:         userId,
+		Status:         status,
+		Manual:         true,
+		LastActivityAt: model.GetMillis(),
+		ExpiresAt:      expiresAt,
+	}
+
+	if result := <-a.Srv.Store.Status().SaveOrUpdate(statusObj); result.Err != nil {
+		return result.Err
+	}
+
+	message := model.NewWebSocketEvent(model.WEBSOCKET_EVENT_STATUS_CHANGE, "", "", userId, nil)
+	message.Add("status", status)
+	message.Add("user_id", userId)
+	a.Publish(message)
+
+	if expiresAt > 0 {
+		a.ensureDNDExpiryTicker()
+	}
+
+	return nil
+}
+
+// ensureDNDExpiryTicker lazily starts the background loop that reverts
+// expired manual statuses back to online. It's safe to call repeatedly --
+// the loop only ever starts once per App -- so an App that never handles a
+// timed /dnd never pays for the ticker, while any App that does keeps
+// polling the shared Status table on behalf of the whole cluster.
+func (a *App) ensureDNDExpiryTicker() {
+	onceIface, _ := dndExpiryTickers.LoadOrStore(a, new(sync.Once))
+	onceIface.(*sync.Once).Do(func() {
+		go a.runDNDExpiryTicker()
+	})
+}
+
+func (a *App) runDNDExpiryTicker() {
+	ticker := time.NewTicker(dndExpiryCheckInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		a.clearExpiredManualStatuses()
+	}
+}
+
+func (a *App) clearExpiredManualStatuses() {
+	result := <-a.Srv.Store.Status().GetExpired(model.GetMillis())
+	if result.Err != nil {
+		l4g.Error("Failed to look up expired manual statuses: %v", result.Err.Error())
+		return
+	}
+
+	for _, status := range result.Data.([]*model.Status) {
+		if err := a.SetStatusFromCommand(status.UserId, model.STATUS_ONLINE, 0); err != nil {
+			l4g.Error("Failed to clear expired manual status for user %v: %v", status.UserId, err.Error())
+		}
+	}
+}
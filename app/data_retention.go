@@ -0,0 +1,21 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package app
+
+import (
+	ejobs "github.com/mattermost/mattermost-server/einterfaces/jobs"
+)
+
+var dataRetentionJobInterface func(*App) ejobs.DataRetentionJobInterface
+
+func RegisterDataRetentionJobInterface(f func(*App) ejobs.DataRetentionJobInterface) {
+	dataRetentionJobInterface = f
+}
+
+func (a *App) DataRetention() ejobs.DataRetentionJobInterface {
+	if dataRetentionJobInterface != nil {
+		return dataRetentionJobInterface(a)
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package jobs
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"time"
+
+	l4g "github.com/alecthomas/log4go"
+
+	"github.com/mattermost/mattermost-server/app"
+	ejobs "github.com/mattermost/mattermost-server/einterfaces/jobs"
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+const (
+	DATA_RETENTION_JOB_NAME = "DataRetention"
+
+	// dataRetentionBatchSize caps how many rows are permanently deleted
+	// per loop iteration so a single run doesn't hold long-lived locks or
+	// block a graceful shutdown for too long.
+	dataRetentionBatchSize = 1000
+
+	// dataRetentionScheduleHour is the local hour the job targets; the
+	// scheduler adds jitter on top so every server in a cluster doesn't
+	// hammer the database at the exact same second.
+	dataRetentionScheduleHour  = 2
+	dataRetentionJitterMinutes = 59
+
+	dataRetentionWebsocketEventExpired = "data_retention_posts_expired"
+)
+
+func init() {
+	app.RegisterDataRetentionJobInterface(func(a *app.App) ejobs.DataRetentionJobInterface {
+		return &DataRetentionJobInterfaceImpl{a}
+	})
+}
+
+type DataRetentionJobInterfaceImpl struct {
+	App *app.App
+}
+
+func (i *DataRetentionJobInterfaceImpl) MakeWorker() model.Worker {
+	return &DataRetentionWorker{
+		name:    DATA_RETENTION_JOB_NAME,
+		app:     i.App,
+		stop:    make(chan bool),
+		stopped: make(chan bool, 1),
+		jobs:    make(chan model.Job),
+	}
+}
+
+func (i *DataRetentionJobInterfaceImpl) MakeScheduler() model.Scheduler {
+	return &DataRetentionScheduler{app: i.App}
+}
+
+type DataRetentionWorker struct {
+	name    string
+	app     *app.App
+	stop    chan bool
+	stopped chan bool
+	jobs    chan model.Job
+}
+
+func (w *DataRetentionWorker) JobChannel() chan<- model.Job {
+	return w.jobs
+}
+
+func (w *DataRetentionWorker) Run() {
+	l4g.Debug("Worker %v: Started", w.name)
+
+	for {
+		select {
+		case <-w.stop:
+			l4g.Debug("Worker %v: Received stop signal", w.name)
+			w.stopped <- true
+			return
+		case job := <-w.jobs:
+			w.DoJob(&job)
+		}
+	}
+}
+
+func (w *DataRetentionWorker) Stop() {
+	l4g.Debug("Worker %v: Stopping", w.name)
+	close(w.stop)
+	<-w.stopped
+}
+
+func (w *DataRetentionWorker) DoJob(job *model.Job) {
+	if result := <-w.app.Srv.Store.Job().UpdateStatus(job.Id, model.JOB_STATUS_IN_PROGRESS); result.Err != nil {
+		l4g.Error("Worker %v: Failed to mark job %v in progress: %v", w.name, job.Id, result.Err.Error())
+		return
+	}
+
+	// Canceled via w.Stop() mid-run: the deletion loops poll ctx.Done()
+	// between batches so a shutdown doesn't leave a half-finished batch.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-w.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	cfg := w.app.Config().DataRetentionSettings
+
+	channelPoliciesResult := <-w.app.Srv.Store.Retention().GetChannelPolicies()
+	if channelPoliciesResult.Err != nil {
+		l4g.Error("Worker %v: Failed to load channel retention policies for job %v: %v", w.name, job.Id, channelPoliciesResult.Err.Error())
+		<-w.app.Srv.Store.Job().UpdateStatus(job.Id, model.JOB_STATUS_ERROR)
+		return
+	}
+	channelPolicies := channelPoliciesResult.Data.([]*store.ChannelRetentionPolicy)
+
+	progress := dataRetentionProgress{}
+	if job.Data != nil {
+		progress.LastPostId = job.Data["last_post_id"]
+	}
+
+	if cfg.EnableMessageDeletion && *cfg.MessageRetentionDays > 0 {
+		endTime := model.GetMillis() - (int64(*cfg.MessageRetentionDays) * 24 * 60 * 60 * 1000)
+		if err := w.deletePosts(ctx, endTime, channelPolicies, job, &progress); err != nil {
+			l4g.Error("Worker %v: Failed to delete expired posts for job %v: %v", w.name, job.Id, err.Error())
+			<-w.app.Srv.Store.Job().UpdateStatus(job.Id, model.JOB_STATUS_ERROR)
+			return
+		}
+	}
+
+	if cfg.EnableFileDeletion && *cfg.FileRetentionDays > 0 {
+		endTime := model.GetMillis() - (int64(*cfg.FileRetentionDays) * 24 * 60 * 60 * 1000)
+		if err := w.deleteFiles(ctx, endTime, channelPolicies, job, &progress); err != nil {
+			l4g.Error("Worker %v: Failed to delete expired files for job %v: %v", w.name, job.Id, err.Error())
+			<-w.app.Srv.Store.Job().UpdateStatus(job.Id, model.JOB_STATUS_ERROR)
+			return
+		}
+	}
+
+	if ctx.Err() != nil {
+		l4g.Debug("Worker %v: Job %v canceled before completion", w.name, job.Id)
+		<-w.app.Srv.Store.Job().UpdateStatus(job.Id, model.JOB_STATUS_CANCELED)
+		return
+	}
+
+	l4g.Info("Worker %v: Job %v finished: processed=%v deleted=%v", w.name, job.Id, progress.Processed, progress.Deleted)
+	<-w.app.Srv.Store.Job().UpdateStatus(job.Id, model.JOB_STATUS_SUCCESS)
+}
+
+// dataRetentionProgress tracks running totals across both the post and file
+// passes of a single job run so they can be persisted to the Jobs row and
+// surfaced if the job is resumed.
+type dataRetentionProgress struct {
+	Processed  int64
+	Deleted    int64
+	LastPostId string
+}
+
+// deletePosts applies each channel's own message retention override (if
+// any) first, then runs the global policy over every other channel.
+// Channels with an override are excluded from the global pass so they
+// aren't purged twice against two different end times.
+func (w *DataRetentionWorker) deletePosts(ctx context.Context, globalEndTime int64, channelPolicies []*store.ChannelRetentionPolicy, job *model.Job, progress *dataRetentionProgress) *model.AppError {
+	var overriddenChannelIds []string
+	for _, policy := range channelPolicies {
+		if policy.MessageRetentionDays == nil {
+			continue
+		}
+		overriddenChannelIds = append(overriddenChannelIds, policy.ChannelId)
+
+		channelEndTime := model.GetMillis() - (int64(*policy.MessageRetentionDays) * 24 * 60 * 60 * 1000)
+		if err := w.deletePostsBatchLoop(ctx, job, progress, func() store.StoreChannel {
+			return w.app.Srv.Store.Post().PermanentDeleteBatchForChannel(policy.ChannelId, channelEndTime, dataRetentionBatchSize)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return w.deletePostsBatchLoop(ctx, job, progress, func() store.StoreChannel {
+		return w.app.Srv.Store.Post().PermanentDeleteBatch(globalEndTime, dataRetentionBatchSize, overriddenChannelIds)
+	})
+}
+
+// deletePostsBatchLoop drives a single PermanentDeleteBatch(ForChannel)
+// call to exhaustion, saving progress and publishing a scoped
+// cache-invalidation event for every channel touched by each batch.
+func (w *DataRetentionWorker) deletePostsBatchLoop(ctx context.Context, job *model.Job, progress *dataRetentionProgress, batch func() store.StoreChannel) *model.AppError {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		result := <-batch()
+		if result.Err != nil {
+			return result.Err
+		}
+		batchResult := result.Data.(*store.PermanentDeleteBatchResult)
+
+		progress.Processed += batchResult.RowsAffected
+		progress.Deleted += batchResult.RowsAffected
+		w.saveProgress(job, progress)
+
+		for _, channelId := range batchResult.ChannelIds {
+			w.publishExpired(channelId)
+		}
+
+		if batchResult.RowsAffected < dataRetentionBatchSize {
+			return nil
+		}
+	}
+}
+
+// deleteFiles mirrors deletePosts for FileInfo rows: per-channel overrides
+// run first, then the global policy over the remaining channels.
+func (w *DataRetentionWorker) deleteFiles(ctx context.Context, globalEndTime int64, channelPolicies []*store.ChannelRetentionPolicy, job *model.Job, progress *dataRetentionProgress) *model.AppError {
+	var overriddenChannelIds []string
+	for _, policy := range channelPolicies {
+		if policy.FileRetentionDays == nil {
+			continue
+		}
+		overriddenChannelIds = append(overriddenChannelIds, policy.ChannelId)
+
+		channelEndTime := model.GetMillis() - (int64(*policy.FileRetentionDays) * 24 * 60 * 60 * 1000)
+		if err := w.deleteFilesBatchLoop(ctx, job, progress,
+			func() store.StoreChannel {
+				return w.app.Srv.Store.FileInfo().GetFilesBatchForRetentionInChannel(policy.ChannelId, channelEndTime, dataRetentionBatchSize)
+			},
+			func() store.StoreChannel {
+				return w.app.Srv.Store.FileInfo().PermanentDeleteBatchForChannel(policy.ChannelId, channelEndTime, dataRetentionBatchSize)
+			},
+		); err != nil {
+			return err
+		}
+	}
+
+	return w.deleteFilesBatchLoop(ctx, job, progress,
+		func() store.StoreChannel {
+			return w.app.Srv.Store.FileInfo().GetFilesBatchForRetention(globalEndTime, dataRetentionBatchSize, overriddenChannelIds)
+		},
+		func() store.StoreChannel {
+			return w.app.Srv.Store.FileInfo().PermanentDeleteBatch(globalEndTime, dataRetentionBatchSize, overriddenChannelIds)
+		},
+	)
+}
+
+func (w *DataRetentionWorker) deleteFilesBatchLoop(ctx context.Context, job *model.Job, progress *dataRetentionProgress, getBatch func() store.StoreChannel, deleteBatch func() store.StoreChannel) *model.AppError {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		getResult := <-getBatch()
+		if getResult.Err != nil {
+			return getResult.Err
+		}
+		infos := getResult.Data.([]*model.FileInfo)
+
+		for _, info := range infos {
+			if rmErr := w.app.RemoveFile(info.Path); rmErr != nil {
+				l4g.Warn("Worker %v: Failed to remove file %v from backend: %v", w.name, info.Path, rmErr.Error())
+			}
+		}
+
+		deleteResult := <-deleteBatch()
+		if deleteResult.Err != nil {
+			return deleteResult.Err
+		}
+		batchResult := deleteResult.Data.(*store.PermanentDeleteBatchResult)
+
+		progress.Processed += int64(len(infos))
+		progress.Deleted += int64(len(infos))
+		w.saveProgress(job, progress)
+
+		for _, channelId := range batchResult.ChannelIds {
+			w.publishExpired(channelId)
+		}
+
+		if len(infos) < dataRetentionBatchSize {
+			return nil
+		}
+	}
+}
+
+// publishExpired tells clients in channelId to drop their cached posts for
+// that channel, rather than broadcasting a bare, unscoped event to every
+// connected client on the server.
+func (w *DataRetentionWorker) publishExpired(channelId string) {
+	message := model.NewWebSocketEvent(dataRetentionWebsocketEventExpired, "", channelId, "", nil)
+	message.Add("channel_id", channelId)
+	w.app.Publish(message)
+}
+
+func (w *DataRetentionWorker) saveProgress(job *model.Job, progress *dataRetentionProgress) {
+	if job.Data == nil {
+		job.Data = make(map[string]string)
+	}
+	job.Data["processed"] = strconv.FormatInt(progress.Processed, 10)
+	job.Data["deleted"] = strconv.FormatInt(progress.Deleted, 10)
+	job.Data["last_post_id"] = progress.LastPostId
+	<-w.app.Srv.Store.Job().UpdateOptimistically(job, job.Status)
+}
+
+type DataRetentionScheduler struct {
+	app *app.App
+}
+
+func (s *DataRetentionScheduler) Name() string {
+	return DATA_RETENTION_JOB_NAME + "Scheduler"
+}
+
+func (s *DataRetentionScheduler) JobType() string {
+	return model.JOB_TYPE_DATA_RETENTION
+}
+
+func (s *DataRetentionScheduler) Enabled(cfg *model.Config) bool {
+	return cfg.DataRetentionSettings.EnableMessageDeletion || cfg.DataRetentionSettings.EnableFileDeletion
+}
+
+// NextScheduleTime runs the job once a night, jittered by up to
+// dataRetentionJitterMinutes so a cluster of servers sharing the same
+// config doesn't all kick off a batch-delete pass at once.
+func (s *DataRetentionScheduler) NextScheduleTime(cfg *model.Config, now time.Time, pendingJobs bool, lastSuccessfulJob *model.Job) *time.Time {
+	jitter := time.Duration(rand.Intn(dataRetentionJitterMinutes)) * time.Minute
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), dataRetentionScheduleHour, 0, 0, 0, now.Location()).Add(jitter)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	return &next
+}
+
+func (s *DataRetentionScheduler) ScheduleJob(cfg *model.Config, pendingJobs bool, lastSuccessfulJob *model.Job) (*model.Job, *model.AppError) {
+	if pendingJobs {
+		return nil, nil
+	}
+
+	job := &model.Job{
+		Id:       model.NewId(),
+		Type:     model.JOB_TYPE_DATA_RETENTION,
+		Status:   model.JOB_STATUS_PENDING,
+		CreateAt: model.GetMillis(),
+	}
+
+	if result := <-s.app.Srv.Store.Job().Save(job); result.Err != nil {
+		return nil, result.Err
+	} else {
+		return result.Data.(*model.Job), nil
+	}
+}
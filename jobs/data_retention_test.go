@@ -0,0 +1,42 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mattermost/mattermost-server/model"
+)
+
+func TestDataRetentionScheduler_NextScheduleTime(t *testing.T) {
+	s := &DataRetentionScheduler{}
+
+	now := time.Date(2018, time.January, 1, 12, 0, 0, 0, time.UTC)
+	next := s.NextScheduleTime(&model.Config{}, now, false, nil)
+
+	if !next.After(now) {
+		t.Fatalf("expected next schedule time %v to be after %v", next, now)
+	}
+
+	if next.Sub(now) > 24*time.Hour {
+		t.Fatalf("expected next schedule time to be within a day, got %v", next.Sub(now))
+	}
+
+	assert.Equal(t, dataRetentionScheduleHour, next.Hour())
+}
+
+func TestDataRetentionScheduler_Enabled(t *testing.T) {
+	s := &DataRetentionScheduler{}
+
+	cfg := &model.Config{}
+	cfg.DataRetentionSettings.EnableMessageDeletion = false
+	cfg.DataRetentionSettings.EnableFileDeletion = false
+	assert.False(t, s.Enabled(cfg))
+
+	cfg.DataRetentionSettings.EnableMessageDeletion = true
+	assert.True(t, s.Enabled(cfg))
+}
@@ -0,0 +1,14 @@
+// Copyright (c) 2017-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package plugin
+
+// API exposes the functionality a plugin's backend can call into the
+// server with. It is intentionally minimal for now and grows as plugin
+// hooks need more capabilities.
+type API interface {
+	// LoadPluginConfiguration populates dest with the plugin's config
+	// block from the server's config.json, the same way
+	// json.Unmarshal would.
+	LoadPluginConfiguration(dest interface{}) error
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2017-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package plugin
+
+// Hooks is the interface a plugin's backend implements to react to
+// activation/deactivation. Plugins may additionally implement Pinger to be
+// health-checked by the Supervisor while running.
+type Hooks interface {
+	// OnActivate is invoked when the plugin is activated.
+	OnActivate(API) error
+
+	// OnDeactivate is invoked when the plugin is deactivated. Plugins that
+	// don't implement Pinger are not health-checked; the Supervisor only
+	// restarts them when their process exits.
+	OnDeactivate() error
+}
+
+// Pinger is implemented by plugins that want the Supervisor to actively
+// health-check their RPC loop on a ticker, rather than only noticing when
+// the process itself dies. Two consecutive missed/errored pings force a
+// restart even though the process is still alive.
+type Pinger interface {
+	Ping() error
+}
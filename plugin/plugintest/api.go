@@ -0,0 +1,26 @@
+// Copyright (c) 2017-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package plugintest
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+// API is a mock of plugin.API for use in tests.
+type API struct {
+	mock.Mock
+}
+
+func (m *API) LoadPluginConfiguration(dest interface{}) error {
+	ret := m.Called(dest)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(interface{}) error); ok {
+		r0 = rf(dest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2017-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package rpcplugin
+
+import "io"
+
+// ioRWC adapts a separate io.Reader and io.Writer into the io.ReadWriteCloser
+// that net/rpc requires for a connection. Hooks and API calls each use their
+// own pair of pipes (stdin/stdout for Hooks, a pair of extra files for API),
+// so neither direction ever has to share or multiplex a single stream.
+type ioRWC struct {
+	r io.Reader
+	w io.Writer
+}
+
+func (rwc *ioRWC) Read(p []byte) (int, error) {
+	return rwc.r.Read(p)
+}
+
+func (rwc *ioRWC) Write(p []byte) (int, error) {
+	return rwc.w.Write(p)
+}
+
+func (rwc *ioRWC) Close() error {
+	var err error
+	if c, ok := rwc.r.(io.Closer); ok {
+		err = c.Close()
+	}
+	if c, ok := rwc.w.(io.Closer); ok {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2017-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package rpcplugin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/rpc"
+	"os"
+
+	"github.com/mattermost/mattermost-server/plugin"
+)
+
+// Main is the entry point a plugin's backend executable calls from its own
+// main() with its Hooks implementation. It serves the Hooks RPC connection
+// (stdin/stdout) for as long as the parent process keeps it open, and
+// returns once that connection is closed.
+//
+// hooksImpl is accepted as interface{}, rather than plugin.Hooks, so that
+// plugins only need to implement the hooks they care about: each method is
+// dispatched via an optional interface check, and missing ones are no-ops.
+func Main(hooksImpl interface{}) {
+	apiConn := &ioRWC{r: os.NewFile(4, "api-response"), w: os.NewFile(3, "api-request")}
+
+	server := rpc.NewServer()
+	server.RegisterName("Hooks", &hooksRPCServer{
+		impl:      hooksImpl,
+		apiClient: rpc.NewClient(apiConn),
+	})
+
+	server.ServeConn(&ioRWC{r: os.Stdin, w: os.Stdout})
+}
+
+type onActivateArgs struct{}
+
+type onActivateReply struct {
+	Error        string
+	SupportsPing bool
+}
+
+type onDeactivateArgs struct{}
+
+type onDeactivateReply struct {
+	Error string
+}
+
+type pingArgs struct{}
+
+type pingReply struct {
+	Error string
+}
+
+type loadPluginConfigurationArgs struct{}
+
+type loadPluginConfigurationReply struct {
+	Config []byte
+	Error  string
+}
+
+// hooksRPCServer runs in the plugin's backend process and dispatches
+// incoming Hooks.* RPC calls to hooksImpl, proxying back into the server's
+// API over apiClient when a hook needs it.
+type hooksRPCServer struct {
+	impl      interface{}
+	apiClient *rpc.Client
+}
+
+func (h *hooksRPCServer) OnActivate(args onActivateArgs, reply *onActivateReply) error {
+	if activator, ok := h.impl.(interface {
+		OnActivate(plugin.API) error
+	}); ok {
+		if err := activator.OnActivate(&apiRPCClient{client: h.apiClient}); err != nil {
+			reply.Error = err.Error()
+		}
+	}
+
+	if _, ok := h.impl.(plugin.Pinger); ok {
+		reply.SupportsPing = true
+	}
+
+	return nil
+}
+
+func (h *hooksRPCServer) OnDeactivate(args onDeactivateArgs, reply *onDeactivateReply) error {
+	if deactivator, ok := h.impl.(interface {
+		OnDeactivate() error
+	}); ok {
+		if err := deactivator.OnDeactivate(); err != nil {
+			reply.Error = err.Error()
+		}
+	}
+
+	return nil
+}
+
+func (h *hooksRPCServer) Ping(args pingArgs, reply *pingReply) error {
+	pinger, ok := h.impl.(plugin.Pinger)
+	if !ok {
+		reply.Error = "plugin does not implement Ping"
+		return nil
+	}
+
+	if err := pinger.Ping(); err != nil {
+		reply.Error = err.Error()
+	}
+
+	return nil
+}
+
+// apiRPCClient runs in the plugin's backend process, implements plugin.API,
+// and forwards calls over the API RPC connection to apiRPCServer in the
+// server process.
+type apiRPCClient struct {
+	client *rpc.Client
+}
+
+func (c *apiRPCClient) LoadPluginConfiguration(dest interface{}) error {
+	var reply loadPluginConfigurationReply
+	if err := c.client.Call("API.LoadPluginConfiguration", loadPluginConfigurationArgs{}, &reply); err != nil {
+		return err
+	}
+	if reply.Error != "" {
+		return errors.New(reply.Error)
+	}
+	if reply.Config == nil {
+		return nil
+	}
+	return json.Unmarshal(reply.Config, dest)
+}
+
+// apiRPCServer runs in the server process and dispatches incoming API.* RPC
+// calls from the plugin's backend process to the real plugin.API.
+//
+// Config values round-trip through an intermediate map so that the two
+// processes never need to agree on a concrete Go config struct type: the
+// caller's own json.Unmarshal into its own struct does the final decode.
+type apiRPCServer struct {
+	api plugin.API
+}
+
+func (s *apiRPCServer) LoadPluginConfiguration(args loadPluginConfigurationArgs, reply *loadPluginConfigurationReply) error {
+	if s.api == nil {
+		reply.Error = "no API available"
+		return nil
+	}
+
+	var cfg map[string]interface{}
+	if err := s.api.LoadPluginConfiguration(&cfg); err != nil {
+		reply.Error = err.Error()
+		return nil
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		reply.Error = err.Error()
+		return nil
+	}
+
+	reply.Config = data
+	return nil
+}
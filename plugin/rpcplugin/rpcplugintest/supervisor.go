@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -30,6 +31,9 @@ func TestSupervisorProvider(t *testing.T, sp SupervisorProviderFunc) {
 		"Supervisor_NonExistentExecutablePath": testSupervisor_NonExistentExecutablePath,
 		"Supervisor_StartTimeout":              testSupervisor_StartTimeout,
 		"Supervisor_PluginCrash":               testSupervisor_PluginCrash,
+		"Supervisor_RestartBackoff":            testSupervisor_RestartBackoff,
+		"Supervisor_MaxRestarts":               testSupervisor_MaxRestarts,
+		"Supervisor_PingTimeout":               testSupervisor_PingTimeout,
 	} {
 		t.Run(name, func(t *testing.T) { f(t, sp) })
 	}
@@ -188,3 +192,185 @@ func testSupervisor_PluginCrash(t *testing.T, sp SupervisorProviderFunc) {
 	assert.True(t, recovered)
 	require.NoError(t, supervisor.Stop())
 }
+
+// A plugin that crashes shortly after every activation should be relaunched
+// with an exponentially growing delay, not hammered in a tight loop.
+func testSupervisor_RestartBackoff(t *testing.T, sp SupervisorProviderFunc) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	backend := filepath.Join(dir, "backend.exe")
+	CompileGo(t, `
+		package main
+
+		import (
+			"os"
+			"time"
+
+			"github.com/mattermost/mattermost-server/plugin"
+			"github.com/mattermost/mattermost-server/plugin/rpcplugin"
+		)
+
+		type MyPlugin struct{}
+
+		func (p *MyPlugin) OnActivate(api plugin.API) error {
+			var config struct{}
+			api.LoadPluginConfiguration(&config)
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				os.Exit(1)
+			}()
+			return nil
+		}
+
+		func main() {
+			rpcplugin.Main(&MyPlugin{})
+		}
+	`, backend)
+
+	ioutil.WriteFile(filepath.Join(dir, "plugin.json"), []byte(`{"id": "foo", "backend": {"executable": "backend.exe"}}`), 0600)
+
+	var api plugintest.API
+	var restarts []time.Time
+	api.On("LoadPluginConfiguration", mock.MatchedBy(func(x interface{}) bool { return true })).Return(func(dest interface{}) error {
+		restarts = append(restarts, time.Now())
+		return nil
+	})
+
+	bundle := model.BundleInfoForPath(dir)
+	supervisor, err := sp(bundle)
+	require.NoError(t, err)
+	require.NoError(t, supervisor.Start(&api))
+	defer supervisor.Stop()
+
+	deadline := time.After(5 * time.Second)
+	for len(restarts) < 4 {
+		select {
+		case <-deadline:
+			t.Fatalf("expected at least 4 restarts, got %d", len(restarts))
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	for i := 2; i < 4; i++ {
+		gap := restarts[i].Sub(restarts[i-1])
+		prevGap := restarts[i-1].Sub(restarts[i-2])
+		assert.True(t, gap >= prevGap, "expected restart delay to grow geometrically, gap %v was not >= previous gap %v", gap, prevGap)
+	}
+}
+
+// After enough consecutive crashes, the supervisor should give up on the
+// plugin instead of restarting it forever.
+func testSupervisor_MaxRestarts(t *testing.T, sp SupervisorProviderFunc) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	backend := filepath.Join(dir, "backend.exe")
+	CompileGo(t, `
+		package main
+
+		import (
+			"os"
+			"time"
+
+			"github.com/mattermost/mattermost-server/plugin"
+			"github.com/mattermost/mattermost-server/plugin/rpcplugin"
+		)
+
+		type MyPlugin struct{}
+
+		func (p *MyPlugin) OnActivate(api plugin.API) error {
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				os.Exit(1)
+			}()
+			return nil
+		}
+
+		func main() {
+			rpcplugin.Main(&MyPlugin{})
+		}
+	`, backend)
+
+	ioutil.WriteFile(filepath.Join(dir, "plugin.json"), []byte(`{"id": "foo", "backend": {"executable": "backend.exe"}}`), 0600)
+
+	var api plugintest.API
+	api.On("LoadPluginConfiguration", mock.MatchedBy(func(x interface{}) bool { return true })).Return(nil)
+
+	bundle := model.BundleInfoForPath(dir)
+	supervisor, err := sp(bundle)
+	require.NoError(t, err)
+	require.NoError(t, supervisor.Start(&api))
+	defer supervisor.Stop()
+
+	failedPermanently := false
+	for i := 0; i < 300; i++ {
+		if supervisor.Start(&api) != nil {
+			failedPermanently = true
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.True(t, failedPermanently, "supervisor should mark the plugin failed and refuse to restart it after the restart cap is hit")
+}
+
+// A plugin whose RPC loop hangs, even though the process is still alive,
+// should be restarted once it misses enough health-check pings.
+func testSupervisor_PingTimeout(t *testing.T, sp SupervisorProviderFunc) {
+	dir, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	backend := filepath.Join(dir, "backend.exe")
+	CompileGo(t, `
+		package main
+
+		import (
+			"github.com/mattermost/mattermost-server/plugin"
+			"github.com/mattermost/mattermost-server/plugin/rpcplugin"
+		)
+
+		type MyPlugin struct{}
+
+		func (p *MyPlugin) OnActivate(api plugin.API) error {
+			var config struct{}
+			api.LoadPluginConfiguration(&config)
+			return nil
+		}
+
+		func (p *MyPlugin) Ping() error {
+			select {} // never respond
+		}
+
+		func main() {
+			rpcplugin.Main(&MyPlugin{})
+		}
+	`, backend)
+
+	ioutil.WriteFile(filepath.Join(dir, "plugin.json"), []byte(`{"id": "foo", "backend": {"executable": "backend.exe"}}`), 0600)
+
+	var api plugintest.API
+	var restarts int32
+	api.On("LoadPluginConfiguration", mock.MatchedBy(func(x interface{}) bool { return true })).Return(func(dest interface{}) error {
+		atomic.AddInt32(&restarts, 1)
+		return nil
+	})
+
+	bundle := model.BundleInfoForPath(dir)
+	supervisor, err := sp(bundle)
+	require.NoError(t, err)
+	require.NoError(t, supervisor.Start(&api))
+	defer supervisor.Stop()
+
+	restarted := false
+	for i := 0; i < 300; i++ {
+		if atomic.LoadInt32(&restarts) >= 2 {
+			restarted = true
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.True(t, restarted, "a hung plugin should be restarted after missing two consecutive pings")
+}
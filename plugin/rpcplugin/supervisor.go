@@ -0,0 +1,391 @@
+// Copyright (c) 2017-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package rpcplugin
+
+import (
+	"fmt"
+	"net/rpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/plugin"
+)
+
+const (
+	// startTimeout bounds how long Start waits for the plugin's first
+	// OnActivate to complete before giving up on a misbehaving process.
+	startTimeout = 5 * time.Second
+
+	// Restart delay starts here and doubles on each consecutive crash,
+	// capped at maxRestartDelay, so a plugin stuck in a crash loop backs
+	// off instead of being hammered in a tight loop.
+	initialRestartDelay = 200 * time.Millisecond
+	maxRestartDelay     = 30 * time.Second
+
+	// Once a plugin has stayed up this long, a subsequent crash is
+	// treated as a fresh failure instead of a continuation of the same
+	// crash loop.
+	healthyUptimeResetWindow = 60 * time.Second
+
+	// After this many consecutive restarts without a healthy-uptime
+	// reset, the plugin is permanently failed.
+	maxConsecutiveRestarts = 5
+
+	// Plugins implementing Pinger are health-checked on this interval;
+	// missing maxMissedPings in a row forces a restart even though the
+	// process is still alive.
+	pingInterval   = 2 * time.Second
+	pingTimeout    = 1 * time.Second
+	maxMissedPings = 2
+)
+
+// SupervisorProvider launches plugin backends over an RPC connection,
+// satisfying plugin.Supervisor.
+func SupervisorProvider(bundle *model.BundleInfo) (plugin.Supervisor, error) {
+	if bundle.Manifest == nil || bundle.Manifest.Backend == nil || bundle.Manifest.Backend.Executable == "" {
+		return nil, fmt.Errorf("no backend executable specified for plugin")
+	}
+
+	executablePath, err := validateExecutablePath(bundle.Path, bundle.Manifest.Backend.Executable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Supervisor{
+		executablePath: executablePath,
+		stopCh:         make(chan struct{}),
+	}, nil
+}
+
+// validateExecutablePath resolves executable against bundlePath and rejects
+// anything that would escape the plugin's own bundle directory.
+func validateExecutablePath(bundlePath, executable string) (string, error) {
+	full := filepath.Clean(filepath.Join(bundlePath, executable))
+
+	rel, err := filepath.Rel(bundlePath, full)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("invalid backend executable path: %v", executable)
+	}
+
+	return full, nil
+}
+
+// Supervisor launches a plugin's backend executable, restarts it across
+// crashes with an exponentially growing delay, gives up after too many
+// consecutive restarts, and health-checks it via Ping when supported.
+type Supervisor struct {
+	executablePath string
+
+	mu           sync.Mutex
+	api          plugin.API
+	started      bool
+	stopped      bool
+	failed       bool
+	cmd          *exec.Cmd
+	cmdDone      chan struct{}
+	client       *rpc.Client
+	supportsPing bool
+	lastStartAt  time.Time
+	restartDelay time.Duration
+	restarts     int
+
+	stopCh chan struct{}
+}
+
+var _ plugin.Supervisor = (*Supervisor)(nil)
+
+func (s *Supervisor) Start(api plugin.API) error {
+	s.mu.Lock()
+	if s.failed {
+		s.mu.Unlock()
+		return fmt.Errorf("plugin has failed too many times and will not be restarted")
+	}
+	if s.started {
+		s.mu.Unlock()
+		return nil
+	}
+	s.started = true
+	s.api = api
+	s.mu.Unlock()
+
+	if err := s.launch(); err != nil {
+		s.mu.Lock()
+		s.started = false
+		s.mu.Unlock()
+		return err
+	}
+
+	go s.monitor()
+
+	return nil
+}
+
+func (s *Supervisor) Stop() error {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil
+	}
+	s.stopped = true
+	cmd := s.cmd
+	cmdDone := s.cmdDone
+	client := s.client
+	s.mu.Unlock()
+
+	close(s.stopCh)
+
+	if client != nil {
+		client.Close()
+	}
+	if cmd != nil && cmd.Process != nil {
+		cmd.Process.Kill()
+	}
+	// The single Wait() call for cmd lives in the goroutine launch started
+	// alongside it; calling Wait() again here too would race with it, so
+	// just wait for that goroutine to observe the exit.
+	if cmdDone != nil {
+		<-cmdDone
+	}
+
+	return nil
+}
+
+func (s *Supervisor) Hooks() plugin.Hooks {
+	return &supervisorHooks{supervisor: s}
+}
+
+// launch starts the backend process, wires up its Hooks and API RPC
+// connections, and blocks until OnActivate replies or startTimeout elapses.
+func (s *Supervisor) launch() error {
+	cmd := exec.Command(s.executablePath)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	apiReqR, apiReqW, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	apiRespR, apiRespW, err := os.Pipe()
+	if err != nil {
+		apiReqR.Close()
+		apiReqW.Close()
+		return err
+	}
+	// The child writes API requests to its fd 3 and reads API responses
+	// from its fd 4, mirroring os.NewFile(3/4, ...) in rpcplugin.Main.
+	cmd.ExtraFiles = []*os.File{apiReqW, apiRespR}
+
+	if err := cmd.Start(); err != nil {
+		apiReqR.Close()
+		apiReqW.Close()
+		apiRespR.Close()
+		apiRespW.Close()
+		return err
+	}
+	apiReqW.Close()
+	apiRespR.Close()
+
+	hooksClient := rpc.NewClient(&ioRWC{r: stdout, w: stdin})
+
+	apiServer := rpc.NewServer()
+	apiServer.RegisterName("API", &apiRPCServer{api: s.api})
+	go apiServer.ServeConn(&ioRWC{r: apiReqR, w: apiRespW})
+
+	activated := make(chan error, 1)
+	var reply onActivateReply
+	go func() {
+		activated <- hooksClient.Call("Hooks.OnActivate", onActivateArgs{}, &reply)
+	}()
+
+	select {
+	case err := <-activated:
+		if err != nil {
+			hooksClient.Close()
+			cmd.Process.Kill()
+			cmd.Wait()
+			return err
+		}
+	case <-time.After(startTimeout):
+		hooksClient.Close()
+		cmd.Process.Kill()
+		cmd.Wait()
+		return fmt.Errorf("timed out waiting for plugin to activate")
+	}
+
+	// cmd.Wait must only ever be called once; this is the single goroutine
+	// that does so for this process, and watch/Stop both just wait on
+	// cmdDone instead of calling Wait themselves.
+	cmdDone := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(cmdDone)
+	}()
+
+	s.mu.Lock()
+	s.cmd = cmd
+	s.cmdDone = cmdDone
+	s.client = hooksClient
+	s.supportsPing = reply.SupportsPing
+	s.lastStartAt = time.Now()
+	s.mu.Unlock()
+
+	return nil
+}
+
+// monitor runs for the life of the Supervisor, watching the current process
+// and relaunching it with backoff after it dies or fails health checks.
+func (s *Supervisor) monitor() {
+	for {
+		s.mu.Lock()
+		cmd := s.cmd
+		cmdDone := s.cmdDone
+		client := s.client
+		supportsPing := s.supportsPing
+		startedAt := s.lastStartAt
+		s.mu.Unlock()
+
+		if cmd != nil {
+			s.watch(cmd, cmdDone, client, supportsPing)
+		}
+
+		select {
+		case <-s.stopCh:
+			return
+		default:
+		}
+
+		s.mu.Lock()
+		s.cmd = nil
+		s.cmdDone = nil
+		s.client = nil
+
+		if cmd != nil && time.Since(startedAt) >= healthyUptimeResetWindow {
+			s.restarts = 0
+			s.restartDelay = 0
+		}
+		s.restarts++
+		restarts := s.restarts
+		if s.restartDelay == 0 {
+			s.restartDelay = initialRestartDelay
+		}
+		delay := s.restartDelay
+		s.mu.Unlock()
+
+		if restarts > maxConsecutiveRestarts {
+			s.mu.Lock()
+			s.failed = true
+			s.mu.Unlock()
+			return
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-s.stopCh:
+			return
+		}
+
+		s.mu.Lock()
+		next := s.restartDelay * 2
+		if next > maxRestartDelay {
+			next = maxRestartDelay
+		}
+		s.restartDelay = next
+		s.mu.Unlock()
+
+		if err := s.launch(); err != nil {
+			continue
+		}
+	}
+}
+
+// watch blocks until cmd exits on its own, the Supervisor is stopped, or (for
+// plugins implementing Pinger) the plugin misses maxMissedPings in a row, in
+// which case cmd is killed to force a restart. cmd.Wait is never called
+// here directly -- cmdDone is closed by the single Wait goroutine launch
+// started, since two goroutines calling Wait on the same *exec.Cmd race on
+// its process state.
+func (s *Supervisor) watch(cmd *exec.Cmd, cmdDone chan struct{}, client *rpc.Client, supportsPing bool) {
+	var tickCh <-chan time.Time
+	if supportsPing {
+		ticker := time.NewTicker(pingInterval)
+		defer ticker.Stop()
+		tickCh = ticker.C
+	}
+
+	missedPings := 0
+	for {
+		select {
+		case <-cmdDone:
+			return
+		case <-s.stopCh:
+			return
+		case <-tickCh:
+			if s.ping(client) {
+				missedPings = 0
+				continue
+			}
+			missedPings++
+			if missedPings >= maxMissedPings {
+				cmd.Process.Kill()
+				<-cmdDone
+				return
+			}
+		}
+	}
+}
+
+func (s *Supervisor) ping(client *rpc.Client) bool {
+	var reply pingReply
+	call := client.Go("Hooks.Ping", pingArgs{}, &reply, make(chan *rpc.Call, 1))
+
+	select {
+	case <-call.Done:
+		return call.Error == nil && reply.Error == ""
+	case <-time.After(pingTimeout):
+		return false
+	}
+}
+
+// supervisorHooks implements plugin.Hooks on behalf of a Supervisor,
+// transparently proxying to whichever backend process is currently running.
+type supervisorHooks struct {
+	supervisor *Supervisor
+}
+
+func (h *supervisorHooks) OnActivate(api plugin.API) error {
+	return fmt.Errorf("OnActivate is invoked automatically by the Supervisor and cannot be called directly")
+}
+
+func (h *supervisorHooks) OnDeactivate() error {
+	h.supervisor.mu.Lock()
+	client := h.supervisor.client
+	h.supervisor.mu.Unlock()
+
+	if client == nil {
+		return fmt.Errorf("plugin is not currently running")
+	}
+
+	var reply onDeactivateReply
+	if err := client.Call("Hooks.OnDeactivate", onDeactivateArgs{}, &reply); err != nil {
+		return err
+	}
+	if reply.Error != "" {
+		return fmt.Errorf(reply.Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2017-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package plugin
+
+// Supervisor is responsible for launching a plugin's backend executable,
+// restarting it across crashes, and exposing its Hooks while it runs.
+//
+// Implementations are expected to apply an exponential-backoff restart
+// policy, give up after too many consecutive restarts, and -- for plugins
+// implementing Pinger -- force a restart after missed health checks even if
+// the process hasn't exited.
+type Supervisor interface {
+	// Start launches the plugin and blocks until it has activated or
+	// failed to do so. Start returns an error if the plugin has
+	// already been permanently failed by too many consecutive
+	// restarts.
+	Start(api API) error
+
+	// Stop terminates the plugin and stops any restart/health-check
+	// monitoring.
+	Stop() error
+
+	// Hooks returns a handle to the plugin's hooks. It remains valid
+	// across restarts, transparently proxying to whichever process
+	// instance is currently running.
+	Hooks() Hooks
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2015-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlFileInfoStore struct {
+	SqlStore
+}
+
+func NewSqlFileInfoStore(sqlStore SqlStore) store.FileInfoStore {
+	return &SqlFileInfoStore{sqlStore}
+}
+
+func (s *SqlFileInfoStore) Save(info *model.FileInfo) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if info.Id != "" {
+			result.Err = model.NewAppError("SqlFileInfoStore.Save", "store.sql_file_info.save.existing.app_error", nil, "id="+info.Id, http.StatusBadRequest)
+			return
+		}
+
+		info.PreSave()
+		if err := s.GetMaster().Insert(info); err != nil {
+			result.Err = model.NewAppError("SqlFileInfoStore.Save", "store.sql_file_info.save.app_error", nil, "id="+info.Id+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = info
+	})
+}
+
+func (s *SqlFileInfoStore) Get(id string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var info model.FileInfo
+		err := s.GetReplica().SelectOne(&info, "SELECT * FROM FileInfo WHERE Id = :Id AND DeleteAt = 0", map[string]interface{}{"Id": id})
+		if err != nil {
+			result.Err = model.NewAppError("SqlFileInfoStore.Get", "store.sql_file_info.get.app_error", nil, "id="+id+", "+err.Error(), http.StatusNotFound)
+			return
+		}
+
+		result.Data = &info
+	})
+}
+
+// GetFilesBatchForRetention returns up to limit FileInfo rows whose
+// CreateAt is older than endTime, skipping any channel in
+// excludeChannelIds (channels with their own retention override, handled
+// separately by GetFilesBatchForRetentionInChannel), so the data retention
+// job can remove their backing data from the FileBackend before
+// PermanentDeleteBatch drops the rows.
+func (s *SqlFileInfoStore) GetFilesBatchForRetention(endTime int64, limit int64, excludeChannelIds []string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		params := map[string]interface{}{"EndTime": endTime, "Limit": limit}
+		exclusion := fileChannelExclusionClause(excludeChannelIds, params)
+
+		var infos []*model.FileInfo
+		query := "SELECT FileInfo.* FROM FileInfo INNER JOIN Posts ON FileInfo.PostId = Posts.Id WHERE FileInfo.CreateAt < :EndTime" + exclusion + " ORDER BY FileInfo.CreateAt LIMIT :Limit"
+		_, err := s.GetReplica().Select(&infos, query, params)
+		if err != nil {
+			result.Err = model.NewAppError("SqlFileInfoStore.GetFilesBatchForRetention", "store.sql_file_info.get_files_batch_for_retention.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = infos
+	})
+}
+
+// PermanentDeleteBatch removes up to limit FileInfo rows whose CreateAt is
+// older than endTime, skipping any channel in excludeChannelIds.
+func (s *SqlFileInfoStore) PermanentDeleteBatch(endTime int64, limit int64, excludeChannelIds []string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		params := map[string]interface{}{"EndTime": endTime, "Limit": limit}
+		exclusion := fileChannelExclusionClause(excludeChannelIds, params)
+
+		var channelIds []string
+		selectQuery := "SELECT DISTINCT Posts.ChannelId FROM FileInfo INNER JOIN Posts ON FileInfo.PostId = Posts.Id WHERE FileInfo.CreateAt < :EndTime" + exclusion + " LIMIT :Limit"
+		if _, err := s.GetReplica().Select(&channelIds, selectQuery, params); err != nil {
+			result.Err = model.NewAppError("SqlFileInfoStore.PermanentDeleteBatch", "store.sql_file_info.permanent_delete_batch.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		idSubquery := "SELECT FileInfo.Id FROM FileInfo INNER JOIN Posts ON FileInfo.PostId = Posts.Id WHERE FileInfo.CreateAt < :EndTime" + exclusion + " LIMIT :Limit"
+
+		var query string
+		if s.DriverName() == model.DATABASE_DRIVER_POSTGRES {
+			query = "DELETE FROM FileInfo WHERE Id = ANY (ARRAY (" + idSubquery + "))"
+		} else {
+			query = "DELETE FROM FileInfo WHERE Id IN (" + idSubquery + ")"
+		}
+
+		sqlResult, err := s.GetMaster().Exec(query, params)
+		if err != nil {
+			result.Err = model.NewAppError("SqlFileInfoStore.PermanentDeleteBatch", "store.sql_file_info.permanent_delete_batch.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rowsAffected, err := sqlResult.RowsAffected()
+		if err != nil {
+			result.Err = model.NewAppError("SqlFileInfoStore.PermanentDeleteBatch", "store.sql_file_info.permanent_delete_batch.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = &store.PermanentDeleteBatchResult{RowsAffected: rowsAffected, ChannelIds: channelIds}
+	})
+}
+
+// GetFilesBatchForRetentionInChannel and PermanentDeleteBatchForChannel
+// mirror the two methods above, scoped to a single channel's own
+// retention override.
+func (s *SqlFileInfoStore) GetFilesBatchForRetentionInChannel(channelId string, endTime int64, limit int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		params := map[string]interface{}{"ChannelId": channelId, "EndTime": endTime, "Limit": limit}
+
+		var infos []*model.FileInfo
+		query := "SELECT FileInfo.* FROM FileInfo INNER JOIN Posts ON FileInfo.PostId = Posts.Id WHERE Posts.ChannelId = :ChannelId AND FileInfo.CreateAt < :EndTime ORDER BY FileInfo.CreateAt LIMIT :Limit"
+		_, err := s.GetReplica().Select(&infos, query, params)
+		if err != nil {
+			result.Err = model.NewAppError("SqlFileInfoStore.GetFilesBatchForRetentionInChannel", "store.sql_file_info.get_files_batch_for_retention_in_channel.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = infos
+	})
+}
+
+func (s *SqlFileInfoStore) PermanentDeleteBatchForChannel(channelId string, endTime int64, limit int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		params := map[string]interface{}{"ChannelId": channelId, "EndTime": endTime, "Limit": limit}
+		idSubquery := "SELECT FileInfo.Id FROM FileInfo INNER JOIN Posts ON FileInfo.PostId = Posts.Id WHERE Posts.ChannelId = :ChannelId AND FileInfo.CreateAt < :EndTime LIMIT :Limit"
+
+		var query string
+		if s.DriverName() == model.DATABASE_DRIVER_POSTGRES {
+			query = "DELETE FROM FileInfo WHERE Id = ANY (ARRAY (" + idSubquery + "))"
+		} else {
+			query = "DELETE FROM FileInfo WHERE Id IN (" + idSubquery + ")"
+		}
+
+		sqlResult, err := s.GetMaster().Exec(query, params)
+		if err != nil {
+			result.Err = model.NewAppError("SqlFileInfoStore.PermanentDeleteBatchForChannel", "store.sql_file_info.permanent_delete_batch_for_channel.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rowsAffected, err := sqlResult.RowsAffected()
+		if err != nil {
+			result.Err = model.NewAppError("SqlFileInfoStore.PermanentDeleteBatchForChannel", "store.sql_file_info.permanent_delete_batch_for_channel.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = &store.PermanentDeleteBatchResult{RowsAffected: rowsAffected, ChannelIds: []string{channelId}}
+	})
+}
+
+// fileChannelExclusionClause is channelExclusionClause's counterpart for
+// queries that reach ChannelId through the Posts join rather than a
+// column on FileInfo itself.
+func fileChannelExclusionClause(excludeChannelIds []string, params map[string]interface{}) string {
+	if len(excludeChannelIds) == 0 {
+		return ""
+	}
+
+	placeholders := make([]string, len(excludeChannelIds))
+	for i, channelId := range excludeChannelIds {
+		key := "ExcludeChannelId" + strconv.Itoa(i)
+		placeholders[i] = ":" + key
+		params[key] = channelId
+	}
+
+	return " AND Posts.ChannelId NOT IN (" + strings.Join(placeholders, ",") + ")"
+}
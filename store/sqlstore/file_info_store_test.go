@@ -0,0 +1,22 @@
+// Copyright (c) 2015-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/store/storetest"
+)
+
+func TestFileInfoStoreGetFilesBatchForRetention(t *testing.T) {
+	StoreTest(t, storetest.TestFileInfoStoreGetFilesBatchForRetention)
+}
+
+func TestFileInfoStorePermanentDeleteBatch(t *testing.T) {
+	StoreTest(t, storetest.TestFileInfoStorePermanentDeleteBatch)
+}
+
+func TestFileInfoStorePermanentDeleteBatchForChannel(t *testing.T) {
+	StoreTest(t, storetest.TestFileInfoStorePermanentDeleteBatchForChannel)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2015-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlPostStore struct {
+	SqlStore
+}
+
+func NewSqlPostStore(sqlStore SqlStore) store.PostStore {
+	return &SqlPostStore{sqlStore}
+}
+
+func (s *SqlPostStore) Save(post *model.Post) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if post.Id != "" {
+			result.Err = model.NewAppError("SqlPostStore.Save", "store.sql_post.save.existing.app_error", nil, "id="+post.Id, http.StatusBadRequest)
+			return
+		}
+
+		post.PreSave()
+		if err := s.GetMaster().Insert(post); err != nil {
+			result.Err = model.NewAppError("SqlPostStore.Save", "store.sql_post.save.app_error", nil, "id="+post.Id+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = post
+	})
+}
+
+func (s *SqlPostStore) Get(id string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var post model.Post
+		err := s.GetReplica().SelectOne(&post, "SELECT * FROM Posts WHERE Id = :Id AND DeleteAt = 0", map[string]interface{}{"Id": id})
+		if err != nil {
+			result.Err = model.NewAppError("SqlPostStore.Get", "store.sql_post.get.app_error", nil, "id="+id+", "+err.Error(), http.StatusNotFound)
+			return
+		}
+
+		result.Data = &post
+	})
+}
+
+// channelExclusionClause builds a "AND ChannelId NOT IN (...)" fragment for
+// the given ids, registering each as its own named param in params so the
+// caller's query stays a single parameterized statement. Returns "" when
+// excludeChannelIds is empty.
+func channelExclusionClause(excludeChannelIds []string, params map[string]interface{}) string {
+	if len(excludeChannelIds) == 0 {
+		return ""
+	}
+
+	placeholders := make([]string, len(excludeChannelIds))
+	for i, channelId := range excludeChannelIds {
+		key := "ExcludeChannelId" + strconv.Itoa(i)
+		placeholders[i] = ":" + key
+		params[key] = channelId
+	}
+
+	return " AND ChannelId NOT IN (" + strings.Join(placeholders, ",") + ")"
+}
+
+// PermanentDeleteBatch removes up to limit posts whose CreateAt is older
+// than endTime, skipping any channel in excludeChannelIds (those channels
+// carry their own retention override and are handled by
+// PermanentDeleteBatchForChannel instead). Used by the data retention job
+// to expire old messages. Capping each pass at limit -- rather than one
+// unbounded DELETE -- keeps a single run from holding a long-lived lock or
+// blocking a graceful shutdown.
+func (s *SqlPostStore) PermanentDeleteBatch(endTime int64, limit int64, excludeChannelIds []string) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		params := map[string]interface{}{"EndTime": endTime, "Limit": limit}
+		exclusion := channelExclusionClause(excludeChannelIds, params)
+
+		var channelIds []string
+		selectQuery := "SELECT DISTINCT ChannelId FROM Posts WHERE CreateAt < :EndTime" + exclusion + " LIMIT :Limit"
+		if _, err := s.GetReplica().Select(&channelIds, selectQuery, params); err != nil {
+			result.Err = model.NewAppError("SqlPostStore.PermanentDeleteBatch", "store.sql_post.permanent_delete_batch.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var query string
+		if s.DriverName() == model.DATABASE_DRIVER_POSTGRES {
+			query = "DELETE FROM Posts WHERE Id = ANY (ARRAY (SELECT Id FROM Posts WHERE CreateAt < :EndTime" + exclusion + " LIMIT :Limit))"
+		} else {
+			query = "DELETE FROM Posts WHERE CreateAt < :EndTime" + exclusion + " LIMIT :Limit"
+		}
+
+		sqlResult, err := s.GetMaster().Exec(query, params)
+		if err != nil {
+			result.Err = model.NewAppError("SqlPostStore.PermanentDeleteBatch", "store.sql_post.permanent_delete_batch.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rowsAffected, err := sqlResult.RowsAffected()
+		if err != nil {
+			result.Err = model.NewAppError("SqlPostStore.PermanentDeleteBatch", "store.sql_post.permanent_delete_batch.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = &store.PermanentDeleteBatchResult{RowsAffected: rowsAffected, ChannelIds: channelIds}
+	})
+}
+
+// PermanentDeleteBatchForChannel removes up to limit posts older than
+// endTime within a single channel, applying that channel's own retention
+// override instead of the global policy.
+func (s *SqlPostStore) PermanentDeleteBatchForChannel(channelId string, endTime int64, limit int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		params := map[string]interface{}{"ChannelId": channelId, "EndTime": endTime, "Limit": limit}
+
+		var query string
+		if s.DriverName() == model.DATABASE_DRIVER_POSTGRES {
+			query = "DELETE FROM Posts WHERE Id = ANY (ARRAY (SELECT Id FROM Posts WHERE ChannelId = :ChannelId AND CreateAt < :EndTime LIMIT :Limit))"
+		} else {
+			query = "DELETE FROM Posts WHERE ChannelId = :ChannelId AND CreateAt < :EndTime LIMIT :Limit"
+		}
+
+		sqlResult, err := s.GetMaster().Exec(query, params)
+		if err != nil {
+			result.Err = model.NewAppError("SqlPostStore.PermanentDeleteBatchForChannel", "store.sql_post.permanent_delete_batch_for_channel.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rowsAffected, err := sqlResult.RowsAffected()
+		if err != nil {
+			result.Err = model.NewAppError("SqlPostStore.PermanentDeleteBatchForChannel", "store.sql_post.permanent_delete_batch_for_channel.app_error", nil, "channel_id="+channelId+", "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = &store.PermanentDeleteBatchResult{RowsAffected: rowsAffected, ChannelIds: []string{channelId}}
+	})
+}
@@ -0,0 +1,22 @@
+// Copyright (c) 2015-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-server/store/storetest"
+)
+
+func TestPostStorePermanentDeleteBatch(t *testing.T) {
+	StoreTest(t, storetest.TestPostStorePermanentDeleteBatch)
+}
+
+func TestPostStorePermanentDeleteBatchExcludeChannel(t *testing.T) {
+	StoreTest(t, storetest.TestPostStorePermanentDeleteBatchExcludeChannel)
+}
+
+func TestPostStorePermanentDeleteBatchForChannel(t *testing.T) {
+	StoreTest(t, storetest.TestPostStorePermanentDeleteBatchForChannel)
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlRetentionPolicyStore struct {
+	SqlStore
+}
+
+func NewSqlRetentionPolicyStore(sqlStore SqlStore) store.RetentionPolicyStore {
+	return &SqlRetentionPolicyStore{sqlStore}
+}
+
+// GetChannelPolicies returns every channel that has its own retention
+// override, read from the ChannelRetentionPolicies table, so the data
+// retention job can honor a per-channel message/file age ahead of (and
+// excluded from) the global batch-delete pass.
+func (s *SqlRetentionPolicyStore) GetChannelPolicies() store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var policies []*store.ChannelRetentionPolicy
+		_, err := s.GetReplica().Select(&policies, "SELECT ChannelId, MessageRetentionDays, FileRetentionDays FROM ChannelRetentionPolicies")
+		if err != nil {
+			result.Err = model.NewAppError("SqlRetentionPolicyStore.GetChannelPolicies", "store.sql_retention_policy.get_channel_policies.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = policies
+	})
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"net/http"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+type SqlStatusStore struct {
+	SqlStore
+}
+
+func NewSqlStatusStore(sqlStore SqlStore) store.StatusStore {
+	return &SqlStatusStore{sqlStore}
+}
+
+func (s *SqlStatusStore) SaveOrUpdate(status *model.Status) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		if err := s.GetMaster().SelectOne(new(model.Status), "SELECT * FROM Status WHERE UserId = :UserId", map[string]interface{}{"UserId": status.UserId}); err == nil {
+			if _, err := s.GetMaster().Update(status); err != nil {
+				result.Err = model.NewAppError("SqlStatusStore.SaveOrUpdate", "store.sql_status.update.app_error", nil, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		if err := s.GetMaster().Insert(status); err != nil {
+			result.Err = model.NewAppError("SqlStatusStore.SaveOrUpdate", "store.sql_status.save.app_error", nil, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// GetExpired returns manual statuses whose ExpiresAt has elapsed as of now,
+// used by the DND expiry ticker to clear timers from a DB lookup instead
+// of an in-process timer tied to whichever node originally set them.
+func (s *SqlStatusStore) GetExpired(now int64) store.StoreChannel {
+	return store.Do(func(result *store.StoreResult) {
+		var statuses []*model.Status
+		_, err := s.GetReplica().Select(&statuses, "SELECT * FROM Status WHERE Manual = :Manual AND ExpiresAt > 0 AND ExpiresAt <= :Now", map[string]interface{}{"Manual": true, "Now": now})
+		if err != nil {
+			result.Err = model.NewAppError("SqlStatusStore.GetExpired", "store.sql_status.get_expired.app_error", nil, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		result.Data = statuses
+	})
+}
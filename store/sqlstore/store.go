@@ -0,0 +1,32 @@
+// Copyright (c) 2015-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package sqlstore
+
+import (
+	"github.com/go-gorp/gorp"
+)
+
+// SqlStore is embedded by every table-specific store (SqlPostStore,
+// SqlFileInfoStore, ...) so they share the same underlying connections
+// instead of each carrying its own master/replica plumbing.
+type SqlStore struct {
+	master   *gorp.DbMap
+	replicas []*gorp.DbMap
+	driver   string
+}
+
+func (ss *SqlStore) GetMaster() *gorp.DbMap {
+	return ss.master
+}
+
+func (ss *SqlStore) GetReplica() *gorp.DbMap {
+	if len(ss.replicas) == 0 {
+		return ss.master
+	}
+	return ss.replicas[0]
+}
+
+func (ss *SqlStore) DriverName() string {
+	return ss.driver
+}
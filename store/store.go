@@ -0,0 +1,125 @@
+// Copyright (c) 2015-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package store
+
+import "github.com/mattermost/mattermost-server/model"
+
+// StoreResult is what every store method delivers on its StoreChannel once
+// the underlying query completes.
+type StoreResult struct {
+	Data interface{}
+	Err  *model.AppError
+}
+
+// StoreChannel is the async-call convention every store method in this
+// package follows: callers receive on it (`<-store.X().Y(...)`)  instead of
+// blocking the caller's goroutine on the query directly.
+type StoreChannel chan StoreResult
+
+// Do runs f in its own goroutine and returns a channel that receives the
+// StoreResult f populates.
+func Do(f func(result *StoreResult)) StoreChannel {
+	channel := make(StoreChannel, 1)
+	go func() {
+		result := StoreResult{}
+		f(&result)
+		channel <- result
+	}()
+	return channel
+}
+
+// Store is the set of table-specific stores the app layer talks to. It only
+// declares the accessors this tree actually calls.
+type Store interface {
+	Post() PostStore
+	FileInfo() FileInfoStore
+	Status() StatusStore
+	Retention() RetentionPolicyStore
+	Job() JobStore
+	User() UserStore
+}
+
+// PermanentDeleteBatchResult is returned by the retention batch-delete
+// methods: how many rows were removed and which channels they belonged
+// to, so callers can scope any resulting client-facing events instead of
+// broadcasting a bare, unscoped notification.
+type PermanentDeleteBatchResult struct {
+	RowsAffected int64
+	ChannelIds   []string
+}
+
+// ChannelRetentionPolicy is a per-channel override of the global message
+// and file retention settings, e.g. a channel kept longer than the global
+// default for a legal hold, or purged sooner than other channels. A nil
+// field means "use the global setting" for that dimension.
+type ChannelRetentionPolicy struct {
+	ChannelId            string
+	MessageRetentionDays *int
+	FileRetentionDays    *int
+}
+
+type RetentionPolicyStore interface {
+	// GetChannelPolicies returns every channel that has its own retention
+	// override, so the data retention job can honor it ahead of (and
+	// exclude it from) the global batch-delete pass.
+	GetChannelPolicies() StoreChannel
+}
+
+type PostStore interface {
+	Save(post *model.Post) StoreChannel
+	Get(id string) StoreChannel
+
+	// PermanentDeleteBatch removes up to limit posts whose CreateAt is
+	// older than endTime, skipping any channel in excludeChannelIds
+	// (channels handled separately via PermanentDeleteBatchForChannel
+	// because they carry their own retention override). Used by the data
+	// retention job to expire old messages in capped batches.
+	PermanentDeleteBatch(endTime int64, limit int64, excludeChannelIds []string) StoreChannel
+
+	// PermanentDeleteBatchForChannel removes up to limit posts older than
+	// endTime within a single channel, applying that channel's own
+	// retention override.
+	PermanentDeleteBatchForChannel(channelId string, endTime int64, limit int64) StoreChannel
+}
+
+type FileInfoStore interface {
+	Save(info *model.FileInfo) StoreChannel
+	Get(id string) StoreChannel
+
+	// GetFilesBatchForRetention returns up to limit FileInfo rows older
+	// than endTime, skipping channels in excludeChannelIds, so the data
+	// retention job can remove their backing data from the FileBackend
+	// before dropping the rows.
+	GetFilesBatchForRetention(endTime int64, limit int64, excludeChannelIds []string) StoreChannel
+
+	// PermanentDeleteBatch removes up to limit FileInfo rows whose
+	// CreateAt is older than endTime, skipping channels in
+	// excludeChannelIds.
+	PermanentDeleteBatch(endTime int64, limit int64, excludeChannelIds []string) StoreChannel
+
+	// GetFilesBatchForRetentionInChannel and PermanentDeleteBatchForChannel
+	// mirror the two methods above, scoped to a single channel's own
+	// retention override.
+	GetFilesBatchForRetentionInChannel(channelId string, endTime int64, limit int64) StoreChannel
+	PermanentDeleteBatchForChannel(channelId string, endTime int64, limit int64) StoreChannel
+}
+
+type StatusStore interface {
+	SaveOrUpdate(status *model.Status) StoreChannel
+
+	// GetExpired returns manual statuses whose ExpiresAt has elapsed as
+	// of now. Used to clear /dnd timers from a periodic lookup rather
+	// than an in-process timer, so any node in a cluster can do it.
+	GetExpired(now int64) StoreChannel
+}
+
+type JobStore interface {
+	Save(job *model.Job) StoreChannel
+	UpdateStatus(id string, status string) StoreChannel
+	UpdateOptimistically(job *model.Job, currentStatus string) StoreChannel
+}
+
+type UserStore interface {
+	GetByUsername(username string) StoreChannel
+}
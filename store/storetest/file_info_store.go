@@ -0,0 +1,103 @@
+// Copyright (c) 2015-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+// TestFileInfoStoreGetFilesBatchForRetention covers FileInfoStore's batch
+// lookup used by the data retention job to find files whose backing data
+// needs to be removed from the FileBackend before their rows are deleted.
+func TestFileInfoStoreGetFilesBatchForRetention(t *testing.T, ss store.Store) {
+	oldPost := (<-ss.Post().Save(&model.Post{ChannelId: model.NewId(), UserId: model.NewId(), Message: "old"})).Data.(*model.Post)
+	newPost := (<-ss.Post().Save(&model.Post{ChannelId: model.NewId(), UserId: model.NewId(), Message: "new"})).Data.(*model.Post)
+
+	o1 := &model.FileInfo{}
+	o1.PostId = oldPost.Id
+	o1.Path = "old/file.png"
+	o1.CreateAt = 1000
+	o1 = (<-ss.FileInfo().Save(o1)).Data.(*model.FileInfo)
+
+	o2 := &model.FileInfo{}
+	o2.PostId = newPost.Id
+	o2.Path = "new/file.png"
+	o2.CreateAt = model.GetMillis()
+	o2 = (<-ss.FileInfo().Save(o2)).Data.(*model.FileInfo)
+
+	result := <-ss.FileInfo().GetFilesBatchForRetention(2000, 1000, nil)
+	require.Nil(t, result.Err)
+
+	infos := result.Data.([]*model.FileInfo)
+	require.Len(t, infos, 1)
+	require.Equal(t, o1.Id, infos[0].Id)
+
+	excluded := <-ss.FileInfo().GetFilesBatchForRetention(2000, 1000, []string{oldPost.ChannelId})
+	require.Nil(t, excluded.Err)
+	require.Len(t, excluded.Data.([]*model.FileInfo), 0, "excluded channel's files should not be returned")
+}
+
+// TestFileInfoStorePermanentDeleteBatch covers FileInfoStore.PermanentDeleteBatch,
+// the batch used by the data retention job to expire old file rows once
+// their backing data has been removed from the FileBackend.
+func TestFileInfoStorePermanentDeleteBatch(t *testing.T, ss store.Store) {
+	oldPost := (<-ss.Post().Save(&model.Post{ChannelId: model.NewId(), UserId: model.NewId(), Message: "old"})).Data.(*model.Post)
+	newPost := (<-ss.Post().Save(&model.Post{ChannelId: model.NewId(), UserId: model.NewId(), Message: "new"})).Data.(*model.Post)
+
+	o1 := &model.FileInfo{}
+	o1.PostId = oldPost.Id
+	o1.Path = "old/file.png"
+	o1.CreateAt = 1000
+	o1 = (<-ss.FileInfo().Save(o1)).Data.(*model.FileInfo)
+
+	o2 := &model.FileInfo{}
+	o2.PostId = newPost.Id
+	o2.Path = "new/file.png"
+	o2.CreateAt = model.GetMillis()
+	o2 = (<-ss.FileInfo().Save(o2)).Data.(*model.FileInfo)
+
+	result := <-ss.FileInfo().PermanentDeleteBatch(2000, 1000, nil)
+	require.Nil(t, result.Err)
+	batchResult := result.Data.(*store.PermanentDeleteBatchResult)
+	require.EqualValues(t, 1, batchResult.RowsAffected, "expected exactly the file older than endTime to be deleted")
+	require.Contains(t, batchResult.ChannelIds, oldPost.ChannelId)
+
+	getResult1 := <-ss.FileInfo().Get(o1.Id)
+	require.NotNil(t, getResult1.Err, "file older than endTime should have been deleted")
+
+	getResult2 := <-ss.FileInfo().Get(o2.Id)
+	require.Nil(t, getResult2.Err, "file newer than endTime should still exist")
+}
+
+// TestFileInfoStorePermanentDeleteBatchForChannel covers the channel-scoped
+// variant used when a channel has its own file retention override.
+func TestFileInfoStorePermanentDeleteBatchForChannel(t *testing.T, ss store.Store) {
+	post := (<-ss.Post().Save(&model.Post{ChannelId: model.NewId(), UserId: model.NewId(), Message: "old"})).Data.(*model.Post)
+	otherPost := (<-ss.Post().Save(&model.Post{ChannelId: model.NewId(), UserId: model.NewId(), Message: "old in other channel"})).Data.(*model.Post)
+
+	o1 := &model.FileInfo{}
+	o1.PostId = post.Id
+	o1.Path = "old/file.png"
+	o1.CreateAt = 1000
+	o1 = (<-ss.FileInfo().Save(o1)).Data.(*model.FileInfo)
+
+	o2 := &model.FileInfo{}
+	o2.PostId = otherPost.Id
+	o2.Path = "other/file.png"
+	o2.CreateAt = 1000
+	o2 = (<-ss.FileInfo().Save(o2)).Data.(*model.FileInfo)
+
+	result := <-ss.FileInfo().PermanentDeleteBatchForChannel(post.ChannelId, 2000, 1000)
+	require.Nil(t, result.Err)
+	batchResult := result.Data.(*store.PermanentDeleteBatchResult)
+	require.EqualValues(t, 1, batchResult.RowsAffected)
+
+	require.NotNil(t, (<-ss.FileInfo().Get(o1.Id)).Err, "file in the targeted channel should have been deleted")
+	require.Nil(t, (<-ss.FileInfo().Get(o2.Id)).Err, "file in the other channel should be untouched")
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2015-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+// TestPostStorePermanentDeleteBatch covers PostStore.PermanentDeleteBatch,
+// the batch used by the data retention job to expire old messages.
+func TestPostStorePermanentDeleteBatch(t *testing.T, ss store.Store) {
+	o1 := &model.Post{}
+	o1.ChannelId = model.NewId()
+	o1.UserId = model.NewId()
+	o1.Message = "old post"
+	o1.CreateAt = 1000
+	o1 = (<-ss.Post().Save(o1)).Data.(*model.Post)
+
+	o2 := &model.Post{}
+	o2.ChannelId = model.NewId()
+	o2.UserId = model.NewId()
+	o2.Message = "new post"
+	o2.CreateAt = model.GetMillis()
+	o2 = (<-ss.Post().Save(o2)).Data.(*model.Post)
+
+	result := <-ss.Post().PermanentDeleteBatch(2000, 1000, nil)
+	require.Nil(t, result.Err)
+	batchResult := result.Data.(*store.PermanentDeleteBatchResult)
+	require.EqualValues(t, 1, batchResult.RowsAffected, "expected exactly the post older than endTime to be deleted")
+	require.Contains(t, batchResult.ChannelIds, o1.ChannelId)
+
+	getResult1 := <-ss.Post().Get(o1.Id)
+	require.NotNil(t, getResult1.Err, "post older than endTime should have been deleted")
+
+	getResult2 := <-ss.Post().Get(o2.Id)
+	require.Nil(t, getResult2.Err, "post newer than endTime should still exist")
+}
+
+// TestPostStorePermanentDeleteBatchExcludeChannel covers the
+// excludeChannelIds parameter the data retention job uses to skip channels
+// that carry their own retention override.
+func TestPostStorePermanentDeleteBatchExcludeChannel(t *testing.T, ss store.Store) {
+	o1 := &model.Post{}
+	o1.ChannelId = model.NewId()
+	o1.UserId = model.NewId()
+	o1.Message = "old post"
+	o1.CreateAt = 1000
+	o1 = (<-ss.Post().Save(o1)).Data.(*model.Post)
+
+	result := <-ss.Post().PermanentDeleteBatch(2000, 1000, []string{o1.ChannelId})
+	require.Nil(t, result.Err)
+	batchResult := result.Data.(*store.PermanentDeleteBatchResult)
+	require.EqualValues(t, 0, batchResult.RowsAffected, "excluded channel's posts should not be deleted")
+
+	getResult := <-ss.Post().Get(o1.Id)
+	require.Nil(t, getResult.Err, "post in excluded channel should still exist")
+}
+
+// TestPostStorePermanentDeleteBatchForChannel covers the channel-scoped
+// variant used when a channel has its own message retention override.
+func TestPostStorePermanentDeleteBatchForChannel(t *testing.T, ss store.Store) {
+	o1 := &model.Post{}
+	o1.ChannelId = model.NewId()
+	o1.UserId = model.NewId()
+	o1.Message = "old post"
+	o1.CreateAt = 1000
+	o1 = (<-ss.Post().Save(o1)).Data.(*model.Post)
+
+	o2 := &model.Post{}
+	o2.ChannelId = model.NewId()
+	o2.UserId = model.NewId()
+	o2.Message = "old post in other channel"
+	o2.CreateAt = 1000
+	o2 = (<-ss.Post().Save(o2)).Data.(*model.Post)
+
+	result := <-ss.Post().PermanentDeleteBatchForChannel(o1.ChannelId, 2000, 1000)
+	require.Nil(t, result.Err)
+	batchResult := result.Data.(*store.PermanentDeleteBatchResult)
+	require.EqualValues(t, 1, batchResult.RowsAffected)
+
+	require.NotNil(t, (<-ss.Post().Get(o1.Id)).Err, "post in the targeted channel should have been deleted")
+	require.Nil(t, (<-ss.Post().Get(o2.Id)).Err, "post in the other channel should be untouched")
+}
@@ -0,0 +1,21 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/store"
+)
+
+// TestRetentionPolicyStoreGetChannelPolicies covers RetentionPolicyStore's
+// lookup of per-channel retention overrides, which the data retention job
+// applies ahead of (and excludes from) its global batch-delete pass.
+func TestRetentionPolicyStoreGetChannelPolicies(t *testing.T, ss store.Store) {
+	result := <-ss.Retention().GetChannelPolicies()
+	require.Nil(t, result.Err)
+	require.IsType(t, []*store.ChannelRetentionPolicy{}, result.Data)
+}
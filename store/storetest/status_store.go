@@ -0,0 +1,52 @@
+// Copyright (c) 2018-present TinkerTech, Inc. All Rights Reserved.
+// See License.txt for license information.
+
+package storetest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/mattermost/mattermost-server/model"
+	"github.com/mattermost/mattermost-server/store"
+)
+
+// TestStatusStoreGetExpired covers StatusStore.GetExpired, the lookup the
+// DND expiry ticker uses to find manual statuses whose timer has elapsed,
+// rather than relying on an in-process timer that a restart or failover
+// would lose.
+func TestStatusStoreGetExpired(t *testing.T, ss store.Store) {
+	expired := &model.Status{
+		UserId:         model.NewId(),
+		Status:         model.STATUS_DND,
+		Manual:         true,
+		LastActivityAt: model.GetMillis(),
+		ExpiresAt:      1000,
+	}
+	require.Nil(t, (<-ss.Status().SaveOrUpdate(expired)).Err)
+
+	notExpired := &model.Status{
+		UserId:         model.NewId(),
+		Status:         model.STATUS_DND,
+		Manual:         true,
+		LastActivityAt: model.GetMillis(),
+		ExpiresAt:      model.GetMillis() + 1000*60*60,
+	}
+	require.Nil(t, (<-ss.Status().SaveOrUpdate(notExpired)).Err)
+
+	noExpiry := &model.Status{
+		UserId:         model.NewId(),
+		Status:         model.STATUS_DND,
+		Manual:         true,
+		LastActivityAt: model.GetMillis(),
+	}
+	require.Nil(t, (<-ss.Status().SaveOrUpdate(noExpiry)).Err)
+
+	result := <-ss.Status().GetExpired(2000)
+	require.Nil(t, result.Err)
+
+	statuses := result.Data.([]*model.Status)
+	require.Len(t, statuses, 1)
+	require.Equal(t, expired.UserId, statuses[0].UserId)
+}